@@ -0,0 +1,36 @@
+// Package abis embeds the contract ABIs used by the helper packages of ethrpc.
+package abis
+
+import _ "embed"
+
+//go:embed AggregatorV3.json
+var AggregatorV3 []byte
+
+//go:embed DmmPool.json
+var DmmPool []byte
+
+//go:embed ERC1155.json
+var ERC1155 []byte
+
+//go:embed ERC20.json
+var ERC20 []byte
+
+// ERC20Bytes32 is the ABI of non-standard ERC-20 tokens which return `bytes32` names and symbols, such as MKR.
+//
+//go:embed ERC20Bytes32.json
+var ERC20Bytes32 []byte
+
+//go:embed ERC4626.json
+var ERC4626 []byte
+
+//go:embed ERC721.json
+var ERC721 []byte
+
+//go:embed Multicall.json
+var Multicall []byte
+
+//go:embed UniswapV2Pair.json
+var UniswapV2Pair []byte
+
+//go:embed UniswapV3Pool.json
+var UniswapV3Pool []byte
@@ -0,0 +1,154 @@
+package ethrpc
+
+import (
+	"math/big"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// batchCallContract sends each of the request's call messages as its own `eth_call`,
+// split into JSON-RPC batches of at most `batchSize` elements.
+// It returns the raw response and the error of every element, in the same order as the calls.
+func (c *Client) batchCallContract(req *Request) ([][]byte, []error, error) {
+	if c.rpcClient == nil {
+		return nil, nil, ErrRPCClientNotSet
+	}
+
+	block, err := req.Block().arg()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	elems := make([]rpc.BatchElem, len(req.RawCallMsgs))
+	results := make([]hexutil.Bytes, len(req.RawCallMsgs))
+	for i, msg := range req.RawCallMsgs {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{toCallArg(msg), block},
+			Result: &results[i],
+		}
+	}
+
+	if err := c.batchCallContext(req, elems); err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([][]byte, len(elems))
+	errs := make([]error, len(elems))
+	for i, elem := range elems {
+		resp[i], errs[i] = results[i], elem.Error
+	}
+
+	return resp, errs, nil
+}
+
+// batchGetStorageAt reads the given storage slots at the request's block using JSON-RPC batches,
+// and unpacks each one with its own arguments.
+func (c *Client) batchGetStorageAt(req *Request, slots []StorageSlot) ([][]interface{}, error) {
+	block, err := req.Block().arg()
+	if err != nil {
+		return nil, err
+	}
+
+	elems := make([]rpc.BatchElem, len(slots))
+	results := make([]hexutil.Bytes, len(slots))
+	for i, slot := range slots {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getStorageAt",
+			Args:   []interface{}{slot.Account, slot.Key, block},
+			Result: &results[i],
+		}
+	}
+
+	if err := c.batchCallContext(req, elems); err != nil {
+		return nil, err
+	}
+
+	res := make([][]interface{}, len(slots))
+	for i, slot := range slots {
+		if elems[i].Error != nil {
+			logger.Errorf("failed to call StorageAt to %v at %v, err: %v", slot.Account, slot.Key, elems[i].Error)
+			return nil, elems[i].Error
+		}
+
+		values, err := slot.ABI.Unpack(results[i])
+		if err != nil {
+			logger.Errorf("failed to unpack StorageAt to %v at %v, err: %v", slot.Account, slot.Key, err)
+			return nil, err
+		}
+
+		res[i] = values
+	}
+
+	return res, nil
+}
+
+// batchCallContext sends the given elements in chunks of at most `batchSize` elements.
+func (c *Client) batchCallContext(req *Request, elems []rpc.BatchElem) error {
+	if c.rpcClient == nil {
+		return ErrRPCClientNotSet
+	}
+
+	batchSize := c.batchSize
+	if batchSize <= 0 {
+		batchSize = len(elems)
+	}
+
+	for start := 0; start < len(elems); start += batchSize {
+		end := start + batchSize
+		if end > len(elems) {
+			end = len(elems)
+		}
+
+		if err := c.rpcClient.BatchCallContext(req.Context(), elems[start:end]); err != nil {
+			logger.Errorf("failed to send batch [%d, %d), err: %v", start, end, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toBlockNumArg is a copy of go-ethereum's ethclient helper.
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	pending := big.NewInt(-1)
+	if number.Cmp(pending) == 0 {
+		return "pending"
+	}
+	finalized := big.NewInt(int64(rpc.FinalizedBlockNumber))
+	if number.Cmp(finalized) == 0 {
+		return "finalized"
+	}
+	safe := big.NewInt(int64(rpc.SafeBlockNumber))
+	if number.Cmp(safe) == 0 {
+		return "safe"
+	}
+	return hexutil.EncodeBig(number)
+}
+
+// toCallArg is a copy of go-ethereum's ethclient helper.
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	return arg
+}
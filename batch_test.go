@@ -0,0 +1,134 @@
+package ethrpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+var errReverted = errors.New("execution reverted")
+
+// batchRPCClient records the size of each batch. `eth_call` elements return the last byte of their
+// call data as a uint256 and `eth_getStorageAt` ones return their key, elements of failing values fail.
+type batchRPCClient struct {
+	RPCClient
+
+	failing map[byte]bool
+	batches []int
+}
+
+func (c *batchRPCClient) BatchCallContext(_ context.Context, elems []rpc.BatchElem) error {
+	c.batches = append(c.batches, len(elems))
+
+	for i := range elems {
+		elem := &elems[i]
+
+		var value byte
+		switch elem.Method {
+		case "eth_call":
+			data := elem.Args[0].(map[string]interface{})["data"].(hexutil.Bytes)
+			value = data[len(data)-1]
+		case "eth_getStorageAt":
+			value = elem.Args[1].(common.Hash)[common.HashLength-1]
+		}
+
+		if c.failing[value] {
+			elem.Error = errReverted
+			continue
+		}
+		*elem.Result.(*hexutil.Bytes) = common.BigToHash(big.NewInt(int64(value))).Bytes()
+	}
+
+	return nil
+}
+
+func TestBatch(t *testing.T) {
+	for _, requireSuccess := range []bool{false, true} {
+		rc := &batchRPCClient{failing: map[byte]bool{4: true}}
+		client := NewWithClient(nil).SetRPCClient(rc).SetBatchSize(2)
+
+		balances := make([]*big.Int, 5)
+		req := client.R().SetRequireSuccess(requireSuccess)
+		for i := range balances {
+			req.AddCall(&Call{
+				ABI:    dmmPoolABI,
+				Target: common.Address{}.Hex(),
+				Method: "balanceOf",
+				Params: []interface{}{common.BigToAddress(big.NewInt(int64(i + 1)))},
+			}, []interface{}{&balances[i]})
+		}
+
+		res, err := req.Batch()
+		require.Equal(t, []int{2, 2, 1}, rc.batches)
+
+		if requireSuccess {
+			require.ErrorIs(t, err, errReverted)
+			continue
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, []bool{true, true, true, false, true}, res.Result)
+		require.Equal(t, errReverted, res.Errors[3])
+		for i, balance := range balances {
+			if i == 3 {
+				require.Nil(t, balance)
+				continue
+			}
+			require.Equal(t, big.NewInt(int64(i+1)), balance)
+		}
+	}
+}
+
+func TestBatchGetStorageAt(t *testing.T) {
+	uint256, err := abi.NewType("uint256", "", nil)
+	require.NoError(t, err)
+
+	rc := &batchRPCClient{}
+	client := NewWithClient(nil).SetRPCClient(rc).SetBatchSize(2)
+
+	slots := make([]StorageSlot, 3)
+	for i := range slots {
+		slots[i] = StorageSlot{Key: common.BigToHash(big.NewInt(int64(i + 1))), ABI: abi.Arguments{{Type: uint256}}}
+	}
+
+	values, err := client.R().BatchGetStorageAt(slots)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 1}, rc.batches)
+	require.Equal(t, [][]interface{}{{big.NewInt(1)}, {big.NewInt(2)}, {big.NewInt(3)}}, values)
+
+	// a failing slot fails the whole read
+	rc.failing = map[byte]bool{2: true}
+	_, err = client.R().BatchGetStorageAt(slots)
+	require.ErrorIs(t, err, errReverted)
+}
+
+// callService serves `eth_call`, returning 7 for every call
+type callService struct{}
+
+func (callService) Call(map[string]interface{}, rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	return common.BigToHash(big.NewInt(7)).Bytes(), nil
+}
+
+func TestNewWithRPCClientBatch(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", callService{}))
+	defer server.Stop()
+
+	client := NewWithRPCClient(rpc.DialInProc(server))
+
+	var totalSupply *big.Int
+	_, err := client.R().AddCall(&Call{
+		ABI:    dmmPoolABI,
+		Target: common.Address{}.Hex(),
+		Method: "totalSupply",
+	}, []interface{}{&totalSupply}).Batch()
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(7), totalSupply)
+}
@@ -0,0 +1,106 @@
+package ethrpc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/KyberNetwork/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BlockTag is a named block of the JSON-RPC block parameter
+type BlockTag string
+
+const (
+	BlockTagLatest    BlockTag = "latest"
+	BlockTagPending   BlockTag = "pending"
+	BlockTagSafe      BlockTag = "safe"
+	BlockTagFinalized BlockTag = "finalized"
+	BlockTagEarliest  BlockTag = "earliest"
+)
+
+// pendingBlockNumber is the block number used by `ethclient` for the pending block
+var pendingBlockNumber = big.NewInt(-1)
+
+// Block selects the block a request is executed at.
+// Hash takes precedence over Tag, which takes precedence over Number.
+// The zero value selects the latest block.
+type Block struct {
+	Number *big.Int
+	Hash   common.Hash
+	// RequireCanonical makes the request fail if the block of Hash is not in the canonical chain
+	RequireCanonical bool
+	Tag              BlockTag
+}
+
+// AtBlockNumber selects the block by its number.
+func AtBlockNumber(number *big.Int) Block {
+	return Block{Number: number}
+}
+
+// AtBlockHash selects the block by its hash, see EIP-1898.
+func AtBlockHash(hash common.Hash, requireCanonical bool) Block {
+	return Block{Hash: hash, RequireCanonical: requireCanonical}
+}
+
+// AtBlockTag selects the block by its tag, such as `finalized`.
+func AtBlockTag(tag BlockTag) Block {
+	return Block{Tag: tag}
+}
+
+// PinBlock resolves the block to its hash, so that several requests read the same state
+// even if they are sent while new blocks are mined. It returns the pinned block along with its header.
+// A block already selected by its hash is kept as is. The pending block can not be pinned,
+// so it returns ErrPendingBlockNotPinnable for it.
+func (c *Client) PinBlock(ctx context.Context, block Block) (Block, *types.Header, error) {
+	header, err := c.HeaderByBlock(ctx, block)
+	if err != nil {
+		logger.Errorf("failed to get header to pin block, err: %v", err)
+		return Block{}, nil, err
+	}
+
+	if block.Hash != zeroHash {
+		return block, header, nil
+	}
+
+	return AtBlockHash(header.Hash(), false), header, nil
+}
+
+// number returns the block number as understood by `ethclient`, nil means latest.
+// An unknown tag returns ErrUnsupportedBlockTag rather than falling back to the latest block.
+func (b Block) number() (*big.Int, error) {
+	switch b.Tag {
+	case "":
+		return b.Number, nil
+	case BlockTagLatest:
+		return nil, nil
+	case BlockTagPending:
+		return pendingBlockNumber, nil
+	case BlockTagSafe:
+		return big.NewInt(int64(rpc.SafeBlockNumber)), nil
+	case BlockTagFinalized:
+		return big.NewInt(int64(rpc.FinalizedBlockNumber)), nil
+	case BlockTagEarliest:
+		return big.NewInt(0), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedBlockTag, b.Tag)
+	}
+}
+
+// arg converts the block into a JSON-RPC block parameter.
+func (b Block) arg() (interface{}, error) {
+	if b.Hash != zeroHash {
+		return rpc.BlockNumberOrHashWithHash(b.Hash, b.RequireCanonical), nil
+	}
+
+	number, err := b.number()
+	if err != nil {
+		return nil, err
+	}
+
+	return toBlockNumArg(number), nil
+}
@@ -0,0 +1,121 @@
+package ethrpc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// routingClient records the method and block each read is sent with
+type routingClient struct {
+	EthClient
+
+	calls []string
+}
+
+func (c *routingClient) record(format string, args ...interface{}) ([]byte, error) {
+	c.calls = append(c.calls, fmt.Sprintf(format, args...))
+	return common.Hash{}.Bytes(), nil
+}
+
+func (c *routingClient) StorageAt(_ context.Context, _ common.Address, _ common.Hash, number *big.Int) ([]byte, error) {
+	return c.record("StorageAt(%v)", number)
+}
+
+func (c *routingClient) PendingStorageAt(context.Context, common.Address, common.Hash) ([]byte, error) {
+	return c.record("PendingStorageAt")
+}
+
+func (c *routingClient) CallContract(_ context.Context, _ ethereum.CallMsg, number *big.Int) ([]byte, error) {
+	return c.record("CallContract(%v)", number)
+}
+
+func (c *routingClient) CallContractAtHash(_ context.Context, _ ethereum.CallMsg, hash common.Hash) ([]byte, error) {
+	return c.record("CallContractAtHash(%s)", hash.TerminalString())
+}
+
+func (c *routingClient) PendingCallContract(context.Context, ethereum.CallMsg) ([]byte, error) {
+	return c.record("PendingCallContract")
+}
+
+func (c *routingClient) CallContext(_ context.Context, result interface{}, method string, args ...interface{}) error {
+	block := args[len(args)-1].(rpc.BlockNumberOrHash)
+	resp, _ := c.record("%s(%s, canonical=%v)", method, block.String(), block.RequireCanonical)
+	*result.(*hexutil.Bytes) = resp
+	return nil
+}
+
+func (c *routingClient) BatchCallContext(context.Context, []rpc.BatchElem) error {
+	return nil
+}
+
+func TestBlockRouting(t *testing.T) {
+	hash := common.HexToHash("0xabcdef")
+	uint256, _ := abi.NewType("uint256", "", nil)
+
+	tests := []struct {
+		block    Block
+		expected []string
+	}{
+		{Block{}, []string{"StorageAt(<nil>)", "CallContract(<nil>)"}},
+		{AtBlockTag(BlockTagLatest), []string{"StorageAt(<nil>)", "CallContract(<nil>)"}},
+		{AtBlockNumber(big.NewInt(5)), []string{"StorageAt(5)", "CallContract(5)"}},
+		{AtBlockTag(BlockTagFinalized), []string{"StorageAt(-3)", "CallContract(-3)"}},
+		{AtBlockTag(BlockTagSafe), []string{"StorageAt(-4)", "CallContract(-4)"}},
+		{AtBlockTag(BlockTagPending), []string{"PendingStorageAt", "PendingCallContract"}},
+		{AtBlockHash(hash, false), []string{
+			"eth_getStorageAt(" + hash.Hex() + ", canonical=false)", "CallContractAtHash(" + hash.TerminalString() + ")",
+		}},
+		{AtBlockHash(hash, true), []string{
+			"eth_getStorageAt(" + hash.Hex() + ", canonical=true)", "eth_call(" + hash.Hex() + ", canonical=true)",
+		}},
+	}
+
+	for _, test := range tests {
+		rc := &routingClient{}
+		client := NewWithClient(rc).SetRPCClient(rc)
+
+		_, err := client.R().SetBlock(test.block).GetStorageAt(common.Address{}, common.Hash{}, abi.Arguments{{Type: uint256}})
+		require.NoError(t, err)
+
+		_, err = client.callContract(client.R().SetBlock(test.block))
+		require.NoError(t, err)
+
+		require.Equal(t, test.expected, rc.calls, "block: %+v", test.block)
+	}
+}
+
+func TestUnsupportedBlockTag(t *testing.T) {
+	rc := &routingClient{}
+	client := NewWithClient(rc).SetRPCClient(rc)
+	uint256, _ := abi.NewType("uint256", "", nil)
+
+	// a misspelt tag must not fall back to the latest block
+	block := AtBlockTag("finalised")
+
+	_, err := client.R().SetBlock(block).GetStorageAt(common.Address{}, common.Hash{}, abi.Arguments{{Type: uint256}})
+	require.ErrorIs(t, err, ErrUnsupportedBlockTag)
+
+	_, err = client.callContract(client.R().SetBlock(block))
+	require.ErrorIs(t, err, ErrUnsupportedBlockTag)
+
+	_, err = client.HeaderByBlock(context.Background(), block)
+	require.ErrorIs(t, err, ErrUnsupportedBlockTag)
+
+	require.Empty(t, rc.calls)
+}
+
+func TestHeaderByBlockPending(t *testing.T) {
+	client := NewWithClient(&routingClient{})
+
+	_, err := client.HeaderByBlock(context.Background(), AtBlockTag(BlockTagPending))
+	require.ErrorIs(t, err, ErrPendingBlockNotPinnable)
+}
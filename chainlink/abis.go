@@ -0,0 +1,20 @@
+package chainlink
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	"github.com/PandaRR007/ethrpc/abis"
+)
+
+// AggregatorV3ABI is the ABI of Chainlink aggregator proxies
+var AggregatorV3ABI abi.ABI
+
+func init() {
+	var err error
+	AggregatorV3ABI, err = abi.JSON(bytes.NewReader(abis.AggregatorV3))
+	if err != nil {
+		panic(err)
+	}
+}
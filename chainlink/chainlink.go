@@ -0,0 +1,163 @@
+// Package chainlink reads many Chainlink price feeds in batches through the multicall contract of an ethrpc.Client.
+package chainlink
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/PandaRR007/ethrpc"
+)
+
+const (
+	MethodDecimals        = "decimals"
+	MethodDescription     = "description"
+	MethodLatestRoundData = "latestRoundData"
+
+	// DefaultPrecision is the default number of decimals prices are scaled to
+	DefaultPrecision = 18
+)
+
+// RoundData is the output of an aggregator's `latestRoundData`
+type RoundData struct {
+	RoundId         *big.Int
+	Answer          *big.Int
+	StartedAt       *big.Int
+	UpdatedAt       *big.Int
+	AnsweredInRound *big.Int
+}
+
+// Price is the latest round of a feed
+type Price struct {
+	Feed        common.Address
+	Description string
+	Decimals    uint8
+	RoundData
+	// Price is the answer scaled to the reader's precision, nil if the round could not be read or is not positive
+	Price *big.Int
+	// Stale is set if the round was not updated within the reader's max age of the block timestamp,
+	// or if it was carried over from a previous round
+	Stale bool
+}
+
+// Prices is the latest round of many feeds, read at the same block
+type Prices struct {
+	BlockNumber    *big.Int
+	BlockTimestamp uint64
+	Prices         []Price
+}
+
+// Reader reads the latest round of many feeds using `tryBlockAndAggregate`,
+// along with the block timestamp from the same multicall to detect stale rounds.
+type Reader struct {
+	client    *ethrpc.Client
+	block     ethrpc.Block
+	maxAge    time.Duration
+	precision uint8
+}
+
+// NewReader creates a new Reader using the client's multicall contract.
+func NewReader(client *ethrpc.Client) *Reader {
+	return &Reader{
+		client:    client,
+		precision: DefaultPrecision,
+	}
+}
+
+// SetBlock pins the block the feeds are read at.
+func (r *Reader) SetBlock(block ethrpc.Block) *Reader {
+	r.block = block
+
+	return r
+}
+
+// SetMaxAge sets how long after its update a round becomes stale. Zero, the default, disables the check.
+func (r *Reader) SetMaxAge(maxAge time.Duration) *Reader {
+	r.maxAge = maxAge
+
+	return r
+}
+
+// SetPrecision sets the number of decimals prices are scaled to.
+func (r *Reader) SetPrecision(precision uint8) *Reader {
+	r.precision = precision
+
+	return r
+}
+
+// GetPrices reads the latest round, decimals and description of the feeds.
+func (r *Reader) GetPrices(ctx context.Context, feeds []common.Address) (*Prices, error) {
+	prices := make([]Price, len(feeds))
+	req := r.client.NewRequest().SetContext(ctx).SetBlock(r.block).SetWithBlockTimestamp(true)
+
+	for i, feed := range feeds {
+		p := &prices[i]
+		p.Feed = feed
+		req.
+			AddCall(newCall(feed, MethodLatestRoundData), []interface{}{&p.RoundData}).
+			AddCall(newCall(feed, MethodDecimals), []interface{}{&p.Decimals}).
+			AddCall(newCall(feed, MethodDescription), []interface{}{&p.Description})
+	}
+
+	res, err := req.TryBlockAndAggregateInChunks()
+	if err != nil {
+		logger.Errorf("failed to get prices, err: %v", err)
+		return nil, err
+	}
+
+	for i := range prices {
+		p := &prices[i]
+		if !res.Result[i*3] || !res.Result[i*3+1] || p.Answer == nil || p.Answer.Sign() <= 0 {
+			continue
+		}
+
+		p.Price = scale(p.Answer, p.Decimals, r.precision)
+		p.Stale = r.isStale(p.RoundData, res.BlockTimestamp)
+	}
+
+	return &Prices{
+		BlockNumber:    res.BlockNumber,
+		BlockTimestamp: res.BlockTimestamp,
+		Prices:         prices,
+	}, nil
+}
+
+func (r *Reader) isStale(round RoundData, blockTimestamp uint64) bool {
+	if round.AnsweredInRound != nil && round.RoundId != nil && round.AnsweredInRound.Cmp(round.RoundId) < 0 {
+		return true
+	}
+
+	if r.maxAge <= 0 || round.UpdatedAt == nil {
+		return false
+	}
+
+	deadline := new(big.Int).Add(round.UpdatedAt, big.NewInt(int64(r.maxAge/time.Second)))
+
+	return deadline.Cmp(new(big.Int).SetUint64(blockTimestamp)) < 0
+}
+
+// scale converts an amount with `from` decimals to `to` decimals.
+func scale(amount *big.Int, from, to uint8) *big.Int {
+	if from == to {
+		return new(big.Int).Set(amount)
+	}
+
+	if from < to {
+		factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(to-from)), nil)
+		return factor.Mul(amount, factor)
+	}
+
+	factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(from-to)), nil)
+	return factor.Quo(amount, factor)
+}
+
+func newCall(feed common.Address, method string) *ethrpc.Call {
+	return &ethrpc.Call{
+		ABI:    AggregatorV3ABI,
+		Target: feed.Hex(),
+		Method: method,
+	}
+}
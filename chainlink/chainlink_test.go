@@ -0,0 +1,114 @@
+package chainlink
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/internal/ethtest"
+)
+
+const now = 1700000000
+
+// feed is the state of a fake aggregator
+type feed struct {
+	decimals uint8
+	round    RoundData
+}
+
+// amount returns n * 10^decimals
+func amount(n, decimals int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil))
+}
+
+func newRound(answer *big.Int, updatedAt uint64, answeredInRound int64) RoundData {
+	return RoundData{
+		RoundId:         big.NewInt(5),
+		Answer:          answer,
+		StartedAt:       new(big.Int).SetUint64(updatedAt),
+		UpdatedAt:       new(big.Int).SetUint64(updatedAt),
+		AnsweredInRound: big.NewInt(answeredInRound),
+	}
+}
+
+// newFeedClient serves the feeds at a head mined at `now`, other addresses revert
+func newFeedClient(feeds map[common.Address]feed) *ethrpc.Client {
+	ec := ethtest.NewMulticall(func(_ ethrpc.Block, target common.Address, data []byte) ([]byte, error) {
+		f, ok := feeds[target]
+		if !ok {
+			return nil, errors.New("execution reverted")
+		}
+
+		method, err := AggregatorV3ABI.MethodById(data[:4])
+		if err != nil {
+			return nil, err
+		}
+
+		switch method.Name {
+		case MethodLatestRoundData:
+			r := f.round
+			return method.Outputs.Pack(r.RoundId, r.Answer, r.StartedAt, r.UpdatedAt, r.AnsweredInRound)
+		case MethodDecimals:
+			return method.Outputs.Pack(f.decimals)
+		default:
+			return method.Outputs.Pack(target.Hex())
+		}
+	})
+	ec.Head.Time = now
+
+	return ethrpc.NewWithClient(ec)
+}
+
+func TestGetPrices(t *testing.T) {
+	var (
+		fresh    = common.HexToAddress("0x5f4ec3df9cbd43714fe2740f5e3616155c5b8419")
+		old      = common.HexToAddress("0xf4030086522a5beea4988f8ca5b36dbc97bee88c")
+		carried  = common.HexToAddress("0x8fffffd4afb6115b954bd326cbe7b4ba576818f6")
+		negative = common.HexToAddress("0x3e7d1eab13ad0104d2750b8863b489d65364e32d")
+		precise  = common.HexToAddress("0xdc530d9457755926550b59e8eccdae7624181557")
+		missing  = common.HexToAddress("0x000000000000000000000000000000000000dead")
+	)
+	feeds := map[common.Address]feed{
+		fresh:    {decimals: 8, round: newRound(amount(2000, 8), now-60, 5)},
+		old:      {decimals: 8, round: newRound(amount(30000, 8), now-2*3600, 5)},
+		carried:  {decimals: 8, round: newRound(amount(1, 8), now-60, 4)},
+		negative: {decimals: 8, round: newRound(big.NewInt(-1), now-60, 5)},
+		precise:  {decimals: 20, round: newRound(amount(3, 20), now-60, 5)},
+	}
+
+	prices, err := NewReader(newFeedClient(feeds)).
+		SetMaxAge(time.Hour).
+		GetPrices(context.Background(), []common.Address{fresh, old, carried, negative, precise, missing})
+	require.NoError(t, err)
+	require.Equal(t, uint64(now), prices.BlockTimestamp)
+
+	type expected struct {
+		price *big.Int
+		stale bool
+	}
+	for i, e := range []expected{
+		{price: amount(2000, 18)},
+		{price: amount(30000, 18), stale: true},
+		{price: amount(1, 18), stale: true},
+		{},
+		{price: amount(3, 18)},
+		{},
+	} {
+		p := prices.Prices[i]
+		require.Equal(t, e.price, p.Price, p.Feed)
+		require.Equal(t, e.stale, p.Stale, p.Feed)
+	}
+	require.Equal(t, fresh.Hex(), prices.Prices[0].Description)
+}
+
+func TestScale(t *testing.T) {
+	require.Equal(t, big.NewInt(123_000), scale(big.NewInt(123), 6, 9))
+	require.Equal(t, big.NewInt(123), scale(big.NewInt(123_456), 9, 6))
+	require.Equal(t, big.NewInt(123), scale(big.NewInt(123), 8, 8))
+}
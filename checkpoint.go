@@ -0,0 +1,67 @@
+package ethrpc
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/PandaRR007/ethrpc/internal/atomicfile"
+)
+
+// CheckpointStore persists the last block processed by a LogScanner.
+type CheckpointStore interface {
+	// Load returns the last processed block, ok is false if there is none yet
+	Load() (block uint64, ok bool, err error)
+	Save(block uint64) error
+}
+
+// FileCheckpointStore is a CheckpointStore backed by a local JSON file
+type FileCheckpointStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+type checkpoint struct {
+	Block uint64 `json:"block"`
+}
+
+// NewFileCheckpointStore creates a new FileCheckpointStore, the file is created on the first save.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{
+		path: path,
+	}
+}
+
+func (s *FileCheckpointStore) Load() (uint64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	var c checkpoint
+	if err = json.Unmarshal(data, &c); err != nil {
+		return 0, false, err
+	}
+
+	return c.Block, true, nil
+}
+
+// Save writes the checkpoint to the file, replacing it atomically.
+func (s *FileCheckpointStore) Save(block uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(checkpoint{Block: block})
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(s.path, data)
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 const (
@@ -20,6 +22,17 @@ const (
 	MethodGetCurrentBlockTimestamp = "getCurrentBlockTimestamp"
 
 	MethodTryBlockAndAggregate = "tryBlockAndAggregate"
+
+	// MethodBatch sends every call as its own `eth_call` inside JSON-RPC batches instead of a multicall
+	MethodBatch = "batch"
+)
+
+const (
+	// DefaultBatchSize is the default maximum number of elements sent in one JSON-RPC batch
+	DefaultBatchSize = 100
+
+	// DefaultMulticallChunkSize is the default maximum number of calls sent in one multicall by helpers which chunk calls
+	DefaultMulticallChunkSize = 500
 )
 
 var zeroHash common.Hash
@@ -34,7 +47,12 @@ type (
 
 type Client struct {
 	ethClient         EthClient
+	rpcClient         RPCClient
 	multiCallContract common.Address
+	batchSize         int
+	multicallChunk    int
+	logWindowSize     uint64
+	logConcurrency    int
 	beforeRequest     []RequestMiddleware
 	afterResponse     []ResponseMiddleware
 }
@@ -45,6 +63,33 @@ func (c *Client) SetMulticallContract(multiCallContract common.Address) *Client
 	return c
 }
 
+// SetRPCClient sets the raw JSON-RPC client used for batch requests.
+func (c *Client) SetRPCClient(rpcClient RPCClient) *Client {
+	c.rpcClient = rpcClient
+
+	return c
+}
+
+// SetBatchSize sets the maximum number of elements sent in one JSON-RPC batch.
+// Requests with more calls are split into several batches.
+func (c *Client) SetBatchSize(batchSize int) *Client {
+	c.batchSize = batchSize
+
+	return c
+}
+
+// SetMulticallChunkSize sets the maximum number of calls sent in one multicall by helpers which chunk calls,
+// such as BalancesAt and Request.ExecuteInChunks.
+func (c *Client) SetMulticallChunkSize(chunkSize int) *Client {
+	c.multicallChunk = chunkSize
+
+	return c
+}
+
+func (c *Client) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.ethClient.ChainID(ctx)
+}
+
 func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
 	return c.ethClient.SuggestGasPrice(ctx)
 }
@@ -57,10 +102,68 @@ func (c *Client) GetBlockNumber(ctx context.Context) (uint64, error) {
 	return c.ethClient.BlockNumber(ctx)
 }
 
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	return c.ethClient.HeaderByHash(ctx, hash)
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return c.ethClient.HeaderByNumber(ctx, number)
+}
+
+// HeaderByBlock returns the header of the selected block, which can be used to pin requests to its hash.
+// The pending block can not be pinned, so it returns ErrPendingBlockNotPinnable for it.
+func (c *Client) HeaderByBlock(ctx context.Context, block Block) (*types.Header, error) {
+	if block.Hash != zeroHash {
+		return c.ethClient.HeaderByHash(ctx, block.Hash)
+	}
+	if block.Tag == BlockTagPending {
+		return nil, ErrPendingBlockNotPinnable
+	}
+
+	number, err := block.number()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.ethClient.HeaderByNumber(ctx, number)
+}
+
 func (c *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
 	return c.ethClient.BalanceAt(ctx, account, blockNumber)
 }
 
+// BalancesAt reads the native balances of many accounts at the given block, nil means latest.
+// Balances are read through the multicall contract's `getEthBalance`, in chunks of the multicall chunk size.
+func (c *Client) BalancesAt(ctx context.Context, accounts []common.Address, blockNumber *big.Int) (map[common.Address]*big.Int, error) {
+	results := make([]*big.Int, len(accounts))
+
+	req := c.R().SetContext(ctx).SetBlockNumber(blockNumber)
+	for i, account := range accounts {
+		req.AddGetEthBalance(account, &results[i])
+	}
+
+	if _, err := req.ExecuteInChunks(MethodAggregate); err != nil {
+		logger.Errorf("failed to get balances, err: %v", err)
+		return nil, err
+	}
+
+	balances := make(map[common.Address]*big.Int, len(accounts))
+	for i, account := range accounts {
+		balances[account] = results[i]
+	}
+
+	return balances, nil
+}
+
+// chunkSize returns the number of calls to send in one multicall for `total` calls.
+func (c *Client) chunkSize(total int) int {
+	if c.multicallChunk <= 0 {
+		return total
+	}
+
+	return c.multicallChunk
+}
+
 func (c *Client) R() *Request {
 	r := &Request{
 		client: c,
@@ -73,8 +176,8 @@ func (c *Client) NewRequest() *Request {
 	return c.R()
 }
 
-func (c *Client) getStorageAt(ctx context.Context, account common.Address, key common.Hash, abi abi.Arguments) ([]interface{}, error) {
-	resp, err := c.ethClient.StorageAt(ctx, account, key, nil)
+func (c *Client) getStorageAt(req *Request, account common.Address, key common.Hash, abi abi.Arguments) ([]interface{}, error) {
+	resp, err := c.storageAt(req, account, key)
 	if err != nil {
 		logger.Errorf("failed to call StorageAt to %v at %v, err: %v", account, key, err)
 		return nil, err
@@ -90,6 +193,38 @@ func (c *Client) getStorageAt(ctx context.Context, account common.Address, key c
 	return res, nil
 }
 
+// storageAt reads the storage slot at the request's block.
+func (c *Client) storageAt(req *Request, account common.Address, key common.Hash) ([]byte, error) {
+	block := req.Block()
+
+	switch {
+	case block.Hash != zeroHash:
+		// `ethclient` can not read storage at a block hash, so read it through the rpc client
+		if c.rpcClient == nil {
+			return nil, ErrRPCClientNotSet
+		}
+
+		arg, err := block.arg()
+		if err != nil {
+			return nil, err
+		}
+
+		var resp hexutil.Bytes
+		err = c.rpcClient.CallContext(req.Context(), &resp, "eth_getStorageAt", account, key, arg)
+
+		return resp, err
+	case block.Tag == BlockTagPending:
+		return c.ethClient.PendingStorageAt(req.Context(), account, key)
+	default:
+		number, err := block.number()
+		if err != nil {
+			return nil, err
+		}
+
+		return c.ethClient.StorageAt(req.Context(), account, key, number)
+	}
+}
+
 func (c *Client) execute(req *Request) (*Response, error) {
 	var err error
 
@@ -100,22 +235,20 @@ func (c *Client) execute(req *Request) (*Response, error) {
 		}
 	}
 
-	var resp []byte
-	if req.BlockHash != zeroHash {
-		resp, err = c.ethClient.CallContractAtHash(req.Context(), req.RawCallMsg, req.BlockHash)
+	response := &Response{
+		Request: req,
+	}
+
+	if req.Method == MethodBatch {
+		response.RawResponses, response.Errors, err = c.batchCallContract(req)
 	} else {
-		resp, err = c.ethClient.CallContract(req.Context(), req.RawCallMsg, req.BlockNumber)
+		response.RawResponse, err = c.callContract(req)
 	}
 	if err != nil {
-		logger.Errorf("failed to call multicall, err: %v", err)
+		logger.Errorf("failed to call %s, err: %v", req.Method, err)
 		return nil, err
 	}
 
-	response := &Response{
-		Request:     req,
-		RawResponse: resp,
-	}
-
 	// Apply Response middleware
 	for _, f := range c.afterResponse {
 		if err = f(c, response); err != nil {
@@ -126,9 +259,47 @@ func (c *Client) execute(req *Request) (*Response, error) {
 	return response, err
 }
 
+// callContract sends the request's call message at the request's block.
+func (c *Client) callContract(req *Request) ([]byte, error) {
+	block := req.Block()
+
+	switch {
+	case block.Hash != zeroHash && block.RequireCanonical:
+		// `ethclient` can not require the block to be canonical, so call it through the rpc client
+		if c.rpcClient == nil {
+			return nil, ErrRPCClientNotSet
+		}
+
+		arg, err := block.arg()
+		if err != nil {
+			return nil, err
+		}
+
+		var resp hexutil.Bytes
+		err = c.rpcClient.CallContext(req.Context(), &resp, "eth_call", toCallArg(req.RawCallMsg), arg)
+
+		return resp, err
+	case block.Hash != zeroHash:
+		return c.ethClient.CallContractAtHash(req.Context(), req.RawCallMsg, block.Hash)
+	case block.Tag == BlockTagPending:
+		return c.ethClient.PendingCallContract(req.Context(), req.RawCallMsg)
+	default:
+		number, err := block.number()
+		if err != nil {
+			return nil, err
+		}
+
+		return c.ethClient.CallContract(req.Context(), req.RawCallMsg, number)
+	}
+}
+
 func createClient(ec EthClient) *Client {
 	c := &Client{
-		ethClient: ec,
+		ethClient:      ec,
+		batchSize:      DefaultBatchSize,
+		multicallChunk: DefaultMulticallChunkSize,
+		logWindowSize:  DefaultLogWindowSize,
+		logConcurrency: DefaultLogConcurrency,
 	}
 
 	// default before request middlewares
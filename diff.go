@@ -0,0 +1,117 @@
+package ethrpc
+
+import (
+	"reflect"
+
+	"github.com/KyberNetwork/logger"
+)
+
+// CallDiffStatus is how the outcome of a call changed between two blocks
+type CallDiffStatus int
+
+const (
+	// CallUnchanged means the call failed at both blocks, or succeeded with the same decoded outputs
+	CallUnchanged CallDiffStatus = iota
+	// CallChanged means the call succeeded at both blocks with different decoded outputs
+	CallChanged
+	// CallNewlyFailing means the call succeeded at the first block and failed at the second one
+	CallNewlyFailing
+	// CallNewlySucceeding means the call failed at the first block and succeeded at the second one
+	CallNewlySucceeding
+)
+
+// CallDiff is the diff of a call executed at two blocks
+type CallDiff struct {
+	Call   *Call
+	Status CallDiffStatus
+	// From and To are the decoded outputs of the call at each block, nil if it failed
+	From []interface{}
+	To   []interface{}
+}
+
+// StateDiff is the diff of a request executed at two blocks
+type StateDiff struct {
+	From  *Response
+	To    *Response
+	Calls []CallDiff
+}
+
+// Changed returns the diffs of the calls whose outcome changed.
+func (d *StateDiff) Changed() []CallDiff {
+	var changed []CallDiff
+	for _, call := range d.Calls {
+		if call.Status != CallUnchanged {
+			changed = append(changed, call)
+		}
+	}
+
+	return changed
+}
+
+// Diff executes copies of the request at two blocks, with `tryAggregate` in chunks, and diffs the decoded outputs
+// of each call. The copies have new outputs of the same types as the request's ones, which are left untouched.
+// Failing calls don't fail the diff, so RequireSuccess is ignored.
+func (r *Request) Diff(from, to Block) (*StateDiff, error) {
+	fromRes, err := r.clone().SetRequireSuccess(false).SetBlock(from).TryAggregateInChunks()
+	if err != nil {
+		logger.Errorf("failed to execute request at the from block, err: %v", err)
+		return nil, err
+	}
+
+	toRes, err := r.clone().SetRequireSuccess(false).SetBlock(to).TryAggregateInChunks()
+	if err != nil {
+		logger.Errorf("failed to execute request at the to block, err: %v", err)
+		return nil, err
+	}
+
+	diff := &StateDiff{
+		From:  fromRes,
+		To:    toRes,
+		Calls: make([]CallDiff, len(r.Calls)),
+	}
+	for i, call := range r.Calls {
+		callDiff := CallDiff{Call: call}
+		if fromRes.Result[i] {
+			callDiff.From = fromRes.Request.Calls[i].Output
+		}
+		if toRes.Result[i] {
+			callDiff.To = toRes.Request.Calls[i].Output
+		}
+
+		switch {
+		case fromRes.Result[i] && !toRes.Result[i]:
+			callDiff.Status = CallNewlyFailing
+		case !fromRes.Result[i] && toRes.Result[i]:
+			callDiff.Status = CallNewlySucceeding
+		case fromRes.Result[i] && !outputsEqual(callDiff.From, callDiff.To):
+			callDiff.Status = CallChanged
+		}
+		diff.Calls[i] = callDiff
+	}
+
+	return diff, nil
+}
+
+// clone copies the request with new outputs for its calls.
+func (r *Request) clone() *Request {
+	clone := *r
+	clone.Calls = make([]*Call, len(r.Calls))
+	for i, call := range r.Calls {
+		clone.Calls[i] = call.clone()
+	}
+
+	return &clone
+}
+
+// clone copies the call with new outputs of the same types.
+func (c *Call) clone() *Call {
+	clone := *c
+	clone.Output = make([]interface{}, len(c.Output))
+	for i, output := range c.Output {
+		if output != nil {
+			clone.Output[i] = reflect.New(reflect.TypeOf(output).Elem()).Interface()
+		}
+	}
+
+	return &clone
+}
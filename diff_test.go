@@ -0,0 +1,66 @@
+package ethrpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// diffEthClient returns the supply of each call at each block, a nil supply makes the call fail
+type diffEthClient struct {
+	EthClient
+
+	supplies map[int64][]*big.Int
+}
+
+func (c *diffEthClient) CallContract(_ context.Context, _ ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result TryAggregateResult
+	for _, supply := range c.supplies[blockNumber.Int64()] {
+		if supply == nil {
+			result = append(result, TryAggregateResultItem{})
+			continue
+		}
+
+		data, err := dmmPoolABI.Methods["totalSupply"].Outputs.Pack(supply)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, TryAggregateResultItem{Success: true, ReturnData: data})
+	}
+
+	return multicallABI.Methods[MethodTryAggregate].Outputs.Pack(result)
+}
+
+func TestRequestDiff(t *testing.T) {
+	ec := &diffEthClient{supplies: map[int64][]*big.Int{
+		1: {big.NewInt(1), big.NewInt(2), big.NewInt(3), nil, nil},
+		2: {big.NewInt(1), big.NewInt(5), nil, big.NewInt(4), nil},
+	}}
+
+	var supply *big.Int
+	req := NewWithClient(ec).R().SetRequireSuccess(true)
+	for i := 0; i < 5; i++ {
+		req.AddCall(&Call{ABI: dmmPoolABI, Target: common.Address{}.Hex(), Method: "totalSupply"}, []interface{}{&supply})
+	}
+
+	diff, err := req.Diff(AtBlockNumber(big.NewInt(1)), AtBlockNumber(big.NewInt(2)))
+	require.NoError(t, err)
+	require.Nil(t, supply)
+
+	var statuses []CallDiffStatus
+	for _, call := range diff.Calls {
+		statuses = append(statuses, call.Status)
+	}
+	require.Equal(t, []CallDiffStatus{CallUnchanged, CallChanged, CallNewlyFailing, CallNewlySucceeding, CallUnchanged}, statuses)
+
+	changed := diff.Changed()
+	require.Len(t, changed, 3)
+	require.Equal(t, big.NewInt(2), *changed[0].From[0].(**big.Int))
+	require.Equal(t, big.NewInt(5), *changed[0].To[0].(**big.Int))
+	require.Nil(t, changed[1].To)
+	require.Nil(t, changed[2].From)
+}
@@ -0,0 +1,143 @@
+package ethrpc
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Enumerator fetches an on-chain indexed collection, such as `allPairsLength`/`allPairs(uint256)`
+// or `totalSupply`/`tokenByIndex(uint256)`, in pages of multicalls. T is the type of an item.
+// The length and all the pages are read at the same block.
+type Enumerator[T any] struct {
+	client       *Client
+	ctx          context.Context
+	block        Block
+	abi          abi.ABI
+	target       common.Address
+	lengthMethod string
+	itemMethod   string
+	offset       uint64
+	pageSize     int
+}
+
+// NewEnumerator creates a new Enumerator of the collection of the target contract,
+// whose length is returned by `lengthMethod()` and items by `itemMethod(uint256)`.
+func NewEnumerator[T any](c *Client, contractABI abi.ABI, target common.Address, lengthMethod, itemMethod string) *Enumerator[T] {
+	return &Enumerator[T]{
+		client:       c,
+		abi:          contractABI,
+		target:       target,
+		lengthMethod: lengthMethod,
+		itemMethod:   itemMethod,
+		pageSize:     c.multicallChunk,
+	}
+}
+
+// SetContext sets the context of all the requests.
+func (e *Enumerator[T]) SetContext(ctx context.Context) *Enumerator[T] {
+	e.ctx = ctx
+
+	return e
+}
+
+// SetBlock sets the block the collection is read at.
+// The enumeration is pinned to the hash of the block, the latest one if it's not set, when it starts,
+// see Client.PinBlock.
+func (e *Enumerator[T]) SetBlock(block Block) *Enumerator[T] {
+	e.block = block
+
+	return e
+}
+
+// SetOffset sets the index of the first item to fetch, to resume an enumeration.
+func (e *Enumerator[T]) SetOffset(offset uint64) *Enumerator[T] {
+	e.offset = offset
+
+	return e
+}
+
+// SetPageSize sets the number of items fetched in each page.
+func (e *Enumerator[T]) SetPageSize(pageSize int) *Enumerator[T] {
+	e.pageSize = pageSize
+
+	return e
+}
+
+// ForEachPage fetches the items from the offset to the end of the collection, page by page,
+// and calls fn with the index of the first item of each page and its items.
+// The offset to resume from after a failed page is the offset of that page.
+func (e *Enumerator[T]) ForEachPage(fn func(offset uint64, items []T) error) error {
+	ctx := e.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	block, _, err := e.client.PinBlock(ctx, e.block)
+	if err != nil {
+		return err
+	}
+
+	var length *big.Int
+	_, err = e.client.R().SetContext(ctx).SetBlock(block).AddCall(&Call{
+		ABI:    e.abi,
+		Target: e.target.Hex(),
+		Method: e.lengthMethod,
+	}, []interface{}{&length}).Call()
+	if err != nil {
+		logger.Errorf("failed to get length of %s on %s, err: %v", e.itemMethod, e.target, err)
+		return err
+	}
+
+	pageSize := uint64(e.pageSize)
+	if e.pageSize <= 0 {
+		pageSize = length.Uint64()
+	}
+
+	for offset := e.offset; offset < length.Uint64(); offset += pageSize {
+		end := offset + pageSize
+		if end > length.Uint64() {
+			end = length.Uint64()
+		}
+
+		items := make([]T, end-offset)
+		req := e.client.R().SetContext(ctx).SetBlock(block).SetRequireSuccess(true)
+		for i := range items {
+			req.AddCall(&Call{
+				ABI:    e.abi,
+				Target: e.target.Hex(),
+				Method: e.itemMethod,
+				Params: []interface{}{new(big.Int).SetUint64(offset + uint64(i))},
+			}, []interface{}{&items[i]})
+		}
+
+		if _, err = req.TryAggregateInChunks(); err != nil {
+			logger.Errorf("failed to get %s [%d, %d) on %s, err: %v", e.itemMethod, offset, end, e.target, err)
+			return err
+		}
+
+		if err = fn(offset, items); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// All fetches all the items from the offset to the end of the collection.
+func (e *Enumerator[T]) All() ([]T, error) {
+	var all []T
+	err := e.ForEachPage(func(_ uint64, items []T) error {
+		all = append(all, items...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
@@ -0,0 +1,44 @@
+package ethrpc_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PandaRR007/ethrpc"
+)
+
+func TestEnumerator(t *testing.T) {
+	var blocks []ethrpc.Block
+	ec := newCollection(10, &blocks)
+	head := ec.Head
+
+	var (
+		offsets []uint64
+		items   []common.Address
+	)
+	err := ethrpc.NewEnumerator[common.Address](ethrpc.NewWithClient(ec), collectionABI, collection, "length", "items").
+		SetOffset(3).
+		SetPageSize(3).
+		ForEachPage(func(offset uint64, page []common.Address) error {
+			offsets = append(offsets, offset)
+			items = append(items, page...)
+
+			return nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 6, 9}, offsets)
+
+	require.Len(t, items, 7)
+	for i, item := range items {
+		require.Equal(t, common.BigToAddress(big.NewInt(int64(i+4))), item)
+	}
+
+	// the length and all the pages are read at the same block
+	require.Len(t, blocks, 8)
+	for _, block := range blocks {
+		require.Equal(t, ethrpc.AtBlockHash(head.Hash(), false), block)
+	}
+}
@@ -0,0 +1,172 @@
+package ethrpc
+
+import (
+	"math/big"
+	"reflect"
+)
+
+var bigIntType = reflect.TypeOf(big.Int{})
+
+// outputsEqual reports whether the decoded outputs of two calls are equal.
+// Unlike reflect.DeepEqual, big integers are compared by value and nil slices equal empty ones.
+func outputsEqual(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !valuesEqual(reflect.ValueOf(a[i]), reflect.ValueOf(b[i])) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func valuesEqual(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+
+		return valuesEqual(a.Elem(), b.Elem())
+	case reflect.Struct:
+		if a.Type() == bigIntType {
+			x, y := a.Interface().(big.Int), b.Interface().(big.Int)
+
+			return x.Cmp(&y) == 0
+		}
+
+		for i := 0; i < a.NumField(); i++ {
+			if a.Type().Field(i).IsExported() && !valuesEqual(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+
+		for i := 0; i < a.Len(); i++ {
+			if !valuesEqual(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+
+		iter := a.MapRange()
+		for iter.Next() {
+			v := b.MapIndex(iter.Key())
+			if !v.IsValid() || !valuesEqual(iter.Value(), v) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return a.Interface() == b.Interface()
+	}
+}
+
+// cloneValue deep copies the value, so that the copy is not changed when the original one is reused.
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		clone := reflect.New(v.Type().Elem())
+		clone.Elem().Set(cloneValue(v.Elem()))
+
+		return clone
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		clone := reflect.New(v.Type()).Elem()
+		clone.Set(cloneValue(v.Elem()))
+
+		return clone
+	case reflect.Struct:
+		clone := reflect.New(v.Type()).Elem()
+		if v.Type() == bigIntType {
+			x := v.Interface().(big.Int)
+			clone.Set(reflect.ValueOf(new(big.Int).Set(&x)).Elem())
+
+			return clone
+		}
+
+		clone.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				clone.Field(i).Set(cloneValue(v.Field(i)))
+			}
+		}
+
+		return clone
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		clone := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			clone.Index(i).Set(cloneValue(v.Index(i)))
+		}
+
+		return clone
+	case reflect.Array:
+		clone := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			clone.Index(i).Set(cloneValue(v.Index(i)))
+		}
+
+		return clone
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+
+		clone := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			clone.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+
+		return clone
+	default:
+		return v
+	}
+}
+
+// cloneOutputs deep copies the outputs of the calls.
+func cloneOutputs(calls []*Call) [][]interface{} {
+	outputs := make([][]interface{}, len(calls))
+	for i, call := range calls {
+		outputs[i] = make([]interface{}, len(call.Output))
+		for j, output := range call.Output {
+			if output != nil {
+				outputs[i][j] = cloneValue(reflect.ValueOf(output)).Interface()
+			}
+		}
+	}
+
+	return outputs
+}
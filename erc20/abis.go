@@ -0,0 +1,35 @@
+package erc20
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	"github.com/PandaRR007/ethrpc/abis"
+)
+
+var (
+	// ABI is the standard ERC-20 ABI
+	ABI abi.ABI
+
+	// bytes32ABI is the ABI of tokens which return `bytes32` names and symbols
+	bytes32ABI abi.ABI
+)
+
+func init() {
+	builder := []struct {
+		ABI  *abi.ABI
+		data []byte
+	}{
+		{&ABI, abis.ERC20},
+		{&bytes32ABI, abis.ERC20Bytes32},
+	}
+
+	for _, b := range builder {
+		var err error
+		*b.ABI, err = abi.JSON(bytes.NewReader(b.data))
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package erc20
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/internal/atomicfile"
+)
+
+// Metadata is the part of a token which almost never changes
+type Metadata struct {
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// MetadataByChain is token metadata keyed by chain ID and token address
+type MetadataByChain map[uint64]map[common.Address]Metadata
+
+// MetadataStore persists the metadata of MetadataCaches.
+// Save adds the metadata to the store, keeping the entries it already has,
+// so that caches sharing a store don't overwrite each other's entries.
+type MetadataStore interface {
+	Load() (MetadataByChain, error)
+	Save(metadata MetadataByChain) error
+}
+
+// FileStore is a MetadataStore backed by a local JSON file
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileStore creates a new FileStore, the file is created on the first save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{
+		path: path,
+	}
+}
+
+// Load reads the metadata from the file, a missing file is empty.
+func (s *FileStore) Load() (MetadataByChain, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.load()
+}
+
+func (s *FileStore) load() (MetadataByChain, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return MetadataByChain{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := MetadataByChain{}
+	if err = json.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// Save merges the metadata into the file, replacing it atomically.
+func (s *FileStore) Save(metadata MetadataByChain) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.load()
+	if err != nil {
+		return err
+	}
+	for chainID, tokens := range metadata {
+		if stored[chainID] == nil {
+			stored[chainID] = make(map[common.Address]Metadata, len(tokens))
+		}
+		for token, m := range tokens {
+			stored[chainID][token] = m
+		}
+	}
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.WriteFile(s.path, data)
+}
+
+// MetadataCache caches token metadata of the client's chain, fetching missing tokens lazily in batches.
+// Each cache keeps its own copy of the metadata loaded from its store, keyed by chain ID.
+type MetadataCache struct {
+	mu       sync.RWMutex
+	reader   *Reader
+	store    MetadataStore
+	chainID  uint64
+	metadata MetadataByChain
+}
+
+// NewMetadataCache creates a new MetadataCache, loading the metadata persisted in the store.
+// The store may be nil to keep the metadata in memory only.
+func NewMetadataCache(client *ethrpc.Client, store MetadataStore) (*MetadataCache, error) {
+	metadata := MetadataByChain{}
+	if store != nil {
+		var err error
+		if metadata, err = store.Load(); err != nil {
+			logger.Errorf("failed to load token metadata, err: %v", err)
+			return nil, err
+		}
+	}
+	if metadata == nil {
+		metadata = MetadataByChain{}
+	}
+
+	return &MetadataCache{
+		reader:   NewReader(client),
+		store:    store,
+		metadata: metadata,
+	}, nil
+}
+
+// Get returns the metadata of the tokens, fetching the ones which are not cached yet in one batch
+// and persisting them to the store. Tokens whose decimals can't be read, such as addresses which are not tokens,
+// are left out of the result and not cached, so they are fetched again by the next Get.
+func (c *MetadataCache) Get(ctx context.Context, tokens []common.Address) (map[common.Address]Metadata, error) {
+	chainID, err := c.getChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[common.Address]Metadata, len(tokens))
+	var missing []common.Address
+
+	c.mu.RLock()
+	for _, token := range tokens {
+		if m, ok := c.metadata[chainID][token]; ok {
+			result[token] = m
+		} else {
+			missing = append(missing, token)
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	fetched, err := c.reader.getTokens(ctx, missing, false)
+	if err != nil {
+		return nil, err
+	}
+
+	added := make(map[common.Address]Metadata, len(fetched))
+	for _, t := range fetched {
+		if !t.Success.Decimals {
+			logger.Debugf("failed to get decimals of token %v, not caching it", t.Address)
+			continue
+		}
+
+		m := Metadata{Name: t.Name, Symbol: t.Symbol, Decimals: t.Decimals}
+		added[t.Address] = m
+		result[t.Address] = m
+	}
+
+	if len(added) == 0 {
+		return result, nil
+	}
+
+	c.mu.Lock()
+	if c.metadata[chainID] == nil {
+		c.metadata[chainID] = map[common.Address]Metadata{}
+	}
+	for token, m := range added {
+		c.metadata[chainID][token] = m
+	}
+	c.mu.Unlock()
+
+	if c.store != nil {
+		if err = c.store.Save(MetadataByChain{chainID: added}); err != nil {
+			logger.Errorf("failed to save token metadata, err: %v", err)
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (c *MetadataCache) getChainID(ctx context.Context) (uint64, error) {
+	c.mu.RLock()
+	chainID := c.chainID
+	c.mu.RUnlock()
+
+	if chainID != 0 {
+		return chainID, nil
+	}
+
+	id, err := c.reader.client.ChainID(ctx)
+	if err != nil {
+		logger.Errorf("failed to get chain id, err: %v", err)
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.chainID = id.Uint64()
+	c.mu.Unlock()
+
+	return id.Uint64(), nil
+}
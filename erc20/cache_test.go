@@ -0,0 +1,110 @@
+package erc20
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/internal/ethtest"
+)
+
+var (
+	usdc = common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	weth = common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	eoa  = common.HexToAddress("0x000000000000000000000000000000000000dead")
+)
+
+// newTokenClient serves the metadata of the tokens, other addresses have no code and return nothing
+func newTokenClient(tokens map[common.Address]Metadata, calls *int) *ethrpc.Client {
+	return ethrpc.NewWithClient(ethtest.NewMulticall(func(_ ethrpc.Block, target common.Address, data []byte) ([]byte, error) {
+		*calls++
+
+		token, ok := tokens[target]
+		if !ok {
+			return nil, nil
+		}
+
+		method, err := ABI.MethodById(data[:4])
+		if err != nil {
+			return nil, err
+		}
+
+		switch method.Name {
+		case MethodName:
+			return method.Outputs.Pack(token.Name)
+		case MethodSymbol:
+			return method.Outputs.Pack(token.Symbol)
+		case MethodDecimals:
+			return method.Outputs.Pack(token.Decimals)
+		default:
+			return nil, errors.New("execution reverted")
+		}
+	}))
+}
+
+func TestMetadataCache(t *testing.T) {
+	tokens := map[common.Address]Metadata{
+		usdc: {Name: "USD Coin", Symbol: "USDC", Decimals: 6},
+		weth: {Name: "Wrapped Ether", Symbol: "WETH", Decimals: 18},
+	}
+	var calls int
+	store := NewFileStore(filepath.Join(t.TempDir(), "tokens.json"))
+
+	cache, err := NewMetadataCache(newTokenClient(tokens, &calls), store)
+	require.NoError(t, err)
+
+	// the address which is not a token is neither returned nor cached
+	result, err := cache.Get(context.Background(), []common.Address{usdc, eoa})
+	require.NoError(t, err)
+	require.Equal(t, map[common.Address]Metadata{usdc: tokens[usdc]}, result)
+	// only the name, symbol and decimals of each token are read
+	require.Equal(t, 6, calls)
+
+	result, err = cache.Get(context.Background(), []common.Address{usdc})
+	require.NoError(t, err)
+	require.Equal(t, map[common.Address]Metadata{usdc: tokens[usdc]}, result)
+	require.Equal(t, 6, calls)
+
+	_, err = cache.Get(context.Background(), []common.Address{eoa})
+	require.NoError(t, err)
+	require.Equal(t, 9, calls)
+
+	// a second cache on the same store adds to its entries
+	other, err := NewMetadataCache(newTokenClient(tokens, &calls), store)
+	require.NoError(t, err)
+	_, err = other.Get(context.Background(), []common.Address{weth})
+	require.NoError(t, err)
+
+	stored, err := store.Load()
+	require.NoError(t, err)
+	require.Equal(t, MetadataByChain{1: tokens}, stored)
+}
+
+func TestFileStore(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "tokens.json"))
+
+	stored, err := store.Load()
+	require.NoError(t, err)
+	require.Empty(t, stored)
+
+	require.NoError(t, store.Save(MetadataByChain{1: {usdc: {Name: "USD Coin", Symbol: "USDC", Decimals: 6}}}))
+	require.NoError(t, store.Save(MetadataByChain{
+		1:  {weth: {Name: "Wrapped Ether", Symbol: "WETH", Decimals: 18}},
+		56: {usdc: {Name: "USD Coin", Symbol: "USDC", Decimals: 18}},
+	}))
+
+	stored, err = NewFileStore(store.path).Load()
+	require.NoError(t, err)
+	require.Equal(t, MetadataByChain{
+		1: {
+			usdc: {Name: "USD Coin", Symbol: "USDC", Decimals: 6},
+			weth: {Name: "Wrapped Ether", Symbol: "WETH", Decimals: 18},
+		},
+		56: {usdc: {Name: "USD Coin", Symbol: "USDC", Decimals: 18}},
+	}, stored)
+}
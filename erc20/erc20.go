@@ -0,0 +1,203 @@
+// Package erc20 reads the state of many ERC-20 tokens in batches through the multicall contract of an ethrpc.Client.
+package erc20
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/PandaRR007/ethrpc"
+)
+
+const (
+	MethodAllowance   = "allowance"
+	MethodBalanceOf   = "balanceOf"
+	MethodDecimals    = "decimals"
+	MethodName        = "name"
+	MethodSymbol      = "symbol"
+	MethodTotalSupply = "totalSupply"
+)
+
+// Token is the metadata and total supply of a token.
+// Fields of calls which reverted are left empty, see Success.
+type Token struct {
+	Address     common.Address
+	Name        string
+	Symbol      string
+	Decimals    uint8
+	TotalSupply *big.Int
+	Success     TokenSuccess
+}
+
+// TokenSuccess tells which calls of a token succeeded.
+// Calls to an address which is not a contract fail as their empty result can't be unpacked.
+type TokenSuccess struct {
+	Name        bool
+	Symbol      bool
+	Decimals    bool
+	TotalSupply bool
+}
+
+// Holder is a token and an account holding it
+type Holder struct {
+	Token  common.Address
+	Holder common.Address
+}
+
+// Allowance is a token, the owner of the tokens and the spender allowed to spend them
+type Allowance struct {
+	Token   common.Address
+	Owner   common.Address
+	Spender common.Address
+}
+
+// Reader reads ERC-20 state in batches using `tryAggregate`, so that a failing token does not fail the others.
+type Reader struct {
+	client *ethrpc.Client
+	block  ethrpc.Block
+}
+
+// NewReader creates a new Reader using the client's multicall contract.
+func NewReader(client *ethrpc.Client) *Reader {
+	return &Reader{
+		client: client,
+	}
+}
+
+// SetBlock pins the block all reads are executed at.
+func (r *Reader) SetBlock(block ethrpc.Block) *Reader {
+	r.block = block
+
+	return r
+}
+
+// GetTokens reads the name, symbol, decimals and total supply of the tokens.
+// Tokens returning `bytes32` names and symbols are supported.
+func (r *Reader) GetTokens(ctx context.Context, tokens []common.Address) ([]Token, error) {
+	return r.getTokens(ctx, tokens, true)
+}
+
+// getTokens reads the name, symbol and decimals of the tokens, along with their total supply if withTotalSupply.
+func (r *Reader) getTokens(ctx context.Context, tokens []common.Address, withTotalSupply bool) ([]Token, error) {
+	type output struct {
+		name, symbol     string
+		name32, symbol32 [32]byte
+		decimals         uint8
+		totalSupply      *big.Int
+	}
+
+	callsPerToken := 3
+	if withTotalSupply {
+		callsPerToken = 4
+	}
+
+	outputs := make([]output, len(tokens))
+	req := r.newRequest(ctx)
+
+	for i, token := range tokens {
+		o := &outputs[i]
+		req.
+			AddCall(newStringCall(token, MethodName), []interface{}{&o.name, &o.name32}).
+			AddCall(newStringCall(token, MethodSymbol), []interface{}{&o.symbol, &o.symbol32}).
+			AddCall(newCall(token, MethodDecimals), []interface{}{&o.decimals})
+		if withTotalSupply {
+			req.AddCall(newCall(token, MethodTotalSupply), []interface{}{&o.totalSupply})
+		}
+	}
+
+	res, err := req.TryAggregateInChunks()
+	if err != nil {
+		logger.Errorf("failed to get tokens, err: %v", err)
+		return nil, err
+	}
+
+	result := make([]Token, len(tokens))
+	for i, token := range tokens {
+		o := outputs[i]
+		result[i] = Token{
+			Address:     token,
+			Name:        stringOrBytes32(o.name, o.name32),
+			Symbol:      stringOrBytes32(o.symbol, o.symbol32),
+			Decimals:    o.decimals,
+			TotalSupply: o.totalSupply,
+			Success: TokenSuccess{
+				Name:     res.Result[callsPerToken*i],
+				Symbol:   res.Result[callsPerToken*i+1],
+				Decimals: res.Result[callsPerToken*i+2],
+			},
+		}
+		if withTotalSupply {
+			result[i].Success.TotalSupply = res.Result[callsPerToken*i+3]
+		}
+	}
+
+	return result, nil
+}
+
+// GetBalances reads the balance of each holder, the balance is nil if the call failed.
+func (r *Reader) GetBalances(ctx context.Context, holders []Holder) ([]*big.Int, error) {
+	balances := make([]*big.Int, len(holders))
+	req := r.newRequest(ctx)
+
+	for i, h := range holders {
+		req.AddCall(newCall(h.Token, MethodBalanceOf, h.Holder), []interface{}{&balances[i]})
+	}
+
+	if _, err := req.TryAggregateInChunks(); err != nil {
+		logger.Errorf("failed to get balances, err: %v", err)
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// GetAllowances reads each allowance, the allowance is nil if the call failed.
+func (r *Reader) GetAllowances(ctx context.Context, allowances []Allowance) ([]*big.Int, error) {
+	result := make([]*big.Int, len(allowances))
+	req := r.newRequest(ctx)
+
+	for i, a := range allowances {
+		req.AddCall(newCall(a.Token, MethodAllowance, a.Owner, a.Spender), []interface{}{&result[i]})
+	}
+
+	if _, err := req.TryAggregateInChunks(); err != nil {
+		logger.Errorf("failed to get allowances, err: %v", err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *Reader) newRequest(ctx context.Context) *ethrpc.Request {
+	return r.client.NewRequest().SetContext(ctx).SetBlock(r.block)
+}
+
+func newCall(token common.Address, method string, params ...interface{}) *ethrpc.Call {
+	return &ethrpc.Call{
+		ABI:    ABI,
+		Target: token.Hex(),
+		Method: method,
+		Params: params,
+	}
+}
+
+// newStringCall creates a call to a method returning a string, falling back to `bytes32` for non-standard tokens.
+func newStringCall(token common.Address, method string) *ethrpc.Call {
+	c := newCall(token, method)
+	c.UnpackABI = []abi.ABI{ABI, bytes32ABI}
+
+	return c
+}
+
+// stringOrBytes32 returns the string if it's set, otherwise the `bytes32` value without its trailing zeros.
+func stringOrBytes32(s string, b [32]byte) string {
+	if s != "" {
+		return s
+	}
+
+	return string(bytes.TrimRight(b[:], "\x00"))
+}
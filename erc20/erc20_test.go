@@ -0,0 +1,88 @@
+package erc20
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/internal/ethtest"
+)
+
+var (
+	mkr        = common.HexToAddress("0x9f8f72aa9304c8b593d555f12ef6589cc3a579a2")
+	noDecimals = common.HexToAddress("0x1f9840a85d5af5bf1d1762f925bdaddc4201f984")
+)
+
+// newERC20Client serves USDC, MKR which returns `bytes32` names and symbols, and a token reverting on
+// `decimals`. Every holder has a balance of 100 and an allowance of 5, other addresses return nothing.
+func newERC20Client() *ethrpc.Client {
+	return ethrpc.NewWithClient(ethtest.NewMulticall(func(_ ethrpc.Block, target common.Address, data []byte) ([]byte, error) {
+		if target != usdc && target != mkr && target != noDecimals {
+			return nil, nil
+		}
+
+		method, err := ABI.MethodById(data[:4])
+		if err != nil {
+			return nil, err
+		}
+
+		switch method.Name {
+		case MethodName, MethodSymbol:
+			if target == mkr {
+				var value [32]byte
+				copy(value[:], map[string]string{MethodName: "Maker", MethodSymbol: "MKR"}[method.Name])
+				return bytes32ABI.Methods[method.Name].Outputs.Pack(value)
+			}
+			return method.Outputs.Pack(map[string]string{MethodName: "USD Coin", MethodSymbol: "USDC"}[method.Name])
+		case MethodDecimals:
+			if target == noDecimals {
+				return nil, errors.New("execution reverted")
+			}
+			return method.Outputs.Pack(uint8(6))
+		case MethodTotalSupply:
+			return method.Outputs.Pack(big.NewInt(1e18))
+		case MethodBalanceOf:
+			return method.Outputs.Pack(big.NewInt(100))
+		default:
+			return method.Outputs.Pack(big.NewInt(5))
+		}
+	}))
+}
+
+func TestGetTokens(t *testing.T) {
+	tokens, err := NewReader(newERC20Client()).GetTokens(context.Background(), []common.Address{usdc, mkr, noDecimals, eoa})
+	require.NoError(t, err)
+
+	all := TokenSuccess{Name: true, Symbol: true, Decimals: true, TotalSupply: true}
+	require.Equal(t, []Token{
+		{Address: usdc, Name: "USD Coin", Symbol: "USDC", Decimals: 6, TotalSupply: big.NewInt(1e18), Success: all},
+		// `bytes32` names and symbols are unpacked with the fallback ABI
+		{Address: mkr, Name: "Maker", Symbol: "MKR", Decimals: 6, TotalSupply: big.NewInt(1e18), Success: all},
+		{
+			Address:     noDecimals,
+			Name:        "USD Coin",
+			Symbol:      "USDC",
+			TotalSupply: big.NewInt(1e18),
+			Success:     TokenSuccess{Name: true, Symbol: true, TotalSupply: true},
+		},
+		// the empty results of an address without code can't be unpacked
+		{Address: eoa},
+	}, tokens)
+}
+
+func TestGetBalancesAndAllowances(t *testing.T) {
+	reader := NewReader(newERC20Client())
+
+	balances, err := reader.GetBalances(context.Background(), []Holder{{Token: usdc, Holder: eoa}, {Token: eoa, Holder: eoa}})
+	require.NoError(t, err)
+	require.Equal(t, []*big.Int{big.NewInt(100), nil}, balances)
+
+	allowances, err := reader.GetAllowances(context.Background(), []Allowance{{Token: mkr, Owner: eoa, Spender: usdc}})
+	require.NoError(t, err)
+	require.Equal(t, []*big.Int{big.NewInt(5)}, allowances)
+}
@@ -0,0 +1,20 @@
+package erc4626
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	"github.com/PandaRR007/ethrpc/abis"
+)
+
+// ABI is the ABI of ERC-4626 vaults
+var ABI abi.ABI
+
+func init() {
+	var err error
+	ABI, err = abi.JSON(bytes.NewReader(abis.ERC4626))
+	if err != nil {
+		panic(err)
+	}
+}
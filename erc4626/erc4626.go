@@ -0,0 +1,154 @@
+// Package erc4626 reads the state of many ERC-4626 vaults in batches through the multicall contract of an ethrpc.Client.
+package erc4626
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/PandaRR007/ethrpc"
+)
+
+const (
+	MethodAsset           = "asset"
+	MethodConvertToAssets = "convertToAssets"
+	MethodDecimals        = "decimals"
+	MethodMaxDeposit      = "maxDeposit"
+	MethodPreviewRedeem   = "previewRedeem"
+	MethodTotalAssets     = "totalAssets"
+	MethodTotalSupply     = "totalSupply"
+)
+
+// Vault is the state of a vault.
+// Fields of calls which reverted are left empty.
+type Vault struct {
+	Address     common.Address
+	Asset       common.Address
+	Decimals    uint8
+	TotalAssets *big.Int
+	TotalSupply *big.Int
+	// SharePrice is `convertToAssets` of one share, i.e. 10^Decimals, in asset units
+	SharePrice *big.Int
+	// RedeemPrice is `previewRedeem` of one share in asset units, which includes the redeem fees
+	RedeemPrice *big.Int
+	// MaxDeposit is `maxDeposit` of the reader's receiver
+	MaxDeposit *big.Int
+	// Success is true if all the calls of the vault succeeded
+	Success bool
+}
+
+// Snapshot is the state of many vaults read at the same block
+type Snapshot struct {
+	BlockNumber *big.Int
+	BlockHash   common.Hash
+	Vaults      []Vault
+}
+
+// Reader reads vaults in two stages pinned to the same block: the vault state and decimals first,
+// and then the conversions of one share which depend on the decimals.
+type Reader struct {
+	client   *ethrpc.Client
+	block    ethrpc.Block
+	receiver common.Address
+}
+
+// NewReader creates a new Reader using the client's multicall contract.
+func NewReader(client *ethrpc.Client) *Reader {
+	return &Reader{
+		client: client,
+	}
+}
+
+// SetBlock sets the block the vaults are read at, the latest block by default.
+// The pending block is not supported as the reads are pinned to the block hash.
+func (r *Reader) SetBlock(block ethrpc.Block) *Reader {
+	r.block = block
+
+	return r
+}
+
+// SetReceiver sets the receiver `maxDeposit` is read for, the zero address by default.
+func (r *Reader) SetReceiver(receiver common.Address) *Reader {
+	r.receiver = receiver
+
+	return r
+}
+
+// GetVaults reads the state of the vaults.
+func (r *Reader) GetVaults(ctx context.Context, vaults []common.Address) (*Snapshot, error) {
+	block, header, err := r.client.PinBlock(ctx, r.block)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]Vault, len(vaults))
+	responses, err := r.client.NewPipeline().
+		SetContext(ctx).
+		SetBlock(block).
+		Then(func(req *ethrpc.Request) error {
+			for i, vault := range vaults {
+				s := &states[i]
+				s.Address = vault
+				req.
+					AddCall(newCall(vault, MethodAsset), []interface{}{&s.Asset}).
+					AddCall(newCall(vault, MethodDecimals), []interface{}{&s.Decimals}).
+					AddCall(newCall(vault, MethodTotalAssets), []interface{}{&s.TotalAssets}).
+					AddCall(newCall(vault, MethodTotalSupply), []interface{}{&s.TotalSupply})
+			}
+
+			return nil
+		}).
+		Then(func(req *ethrpc.Request) error {
+			for i, vault := range vaults {
+				s := &states[i]
+				oneShare := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(s.Decimals)), nil)
+				req.
+					AddCall(newCall(vault, MethodConvertToAssets, oneShare), []interface{}{&s.SharePrice}).
+					AddCall(newCall(vault, MethodPreviewRedeem, oneShare), []interface{}{&s.RedeemPrice}).
+					AddCall(newCall(vault, MethodMaxDeposit, r.receiver), []interface{}{&s.MaxDeposit})
+			}
+
+			return nil
+		}).
+		Execute()
+	if err != nil {
+		logger.Errorf("failed to get vaults, err: %v", err)
+		return nil, err
+	}
+
+	for i := range states {
+		states[i].Success = allSucceeded(responses[0], i*4, 4) && allSucceeded(responses[1], i*3, 3)
+	}
+
+	return &Snapshot{
+		BlockNumber: header.Number,
+		BlockHash:   header.Hash(),
+		Vaults:      states,
+	}, nil
+}
+
+// allSucceeded tells whether the calls [start, start+n) of the response succeeded.
+func allSucceeded(res *ethrpc.Response, start, n int) bool {
+	if res == nil {
+		return false
+	}
+
+	for _, ok := range res.Result[start : start+n] {
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newCall(vault common.Address, method string, params ...interface{}) *ethrpc.Call {
+	return &ethrpc.Call{
+		ABI:    ABI,
+		Target: vault.Hex(),
+		Method: method,
+		Params: params,
+	}
+}
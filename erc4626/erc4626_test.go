@@ -0,0 +1,113 @@
+package erc4626
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/internal/ethtest"
+)
+
+func TestGetVaultsPendingBlock(t *testing.T) {
+	client := ethrpc.NewWithClient(ethtest.NewMulticall(nil))
+
+	_, err := NewReader(client).SetBlock(ethrpc.AtBlockTag(ethrpc.BlockTagPending)).
+		GetVaults(context.Background(), []common.Address{{}})
+	require.ErrorIs(t, err, ethrpc.ErrPendingBlockNotPinnable)
+}
+
+var (
+	usdcVault     = common.HexToAddress("0x5c0a86a32c129538d62c106eb8115a8b02358d57")
+	ethVault      = common.HexToAddress("0xa258c4606ca8206d8aa700ce2143d7db854d168c")
+	noRedeem      = common.HexToAddress("0x83f20f44975d03b1b09e64809b757c47f942beea")
+	asset         = common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	notAVault     = common.HexToAddress("0x000000000000000000000000000000000000dead")
+	vaultDecimals = map[common.Address]uint8{usdcVault: 6, ethVault: 18, noRedeem: 6}
+)
+
+// newVaultClient serves vaults whose shares are worth 1.1 assets, or 1.05 when redeemed.
+// previewRedeem of noRedeem reverts, and other addresses return nothing.
+func newVaultClient() *ethtest.Multicall {
+	return ethtest.NewMulticall(func(_ ethrpc.Block, target common.Address, data []byte) ([]byte, error) {
+		decimals, ok := vaultDecimals[target]
+		if !ok {
+			return nil, nil
+		}
+
+		method, err := ABI.MethodById(data[:4])
+		if err != nil {
+			return nil, err
+		}
+		args, err := method.Inputs.Unpack(data[4:])
+		if err != nil {
+			return nil, err
+		}
+
+		switch method.Name {
+		case MethodAsset:
+			return method.Outputs.Pack(asset)
+		case MethodDecimals:
+			return method.Outputs.Pack(decimals)
+		case MethodTotalAssets:
+			return method.Outputs.Pack(big.NewInt(1100))
+		case MethodTotalSupply:
+			return method.Outputs.Pack(big.NewInt(1000))
+		case MethodConvertToAssets:
+			shares := args[0].(*big.Int)
+			return method.Outputs.Pack(new(big.Int).Div(new(big.Int).Mul(shares, big.NewInt(110)), big.NewInt(100)))
+		case MethodPreviewRedeem:
+			if target == noRedeem {
+				return nil, errors.New("execution reverted")
+			}
+			shares := args[0].(*big.Int)
+			return method.Outputs.Pack(new(big.Int).Div(new(big.Int).Mul(shares, big.NewInt(105)), big.NewInt(100)))
+		default:
+			return method.Outputs.Pack(big.NewInt(500))
+		}
+	})
+}
+
+func TestGetVaults(t *testing.T) {
+	ec := newVaultClient()
+
+	snapshot, err := NewReader(ethrpc.NewWithClient(ec)).
+		GetVaults(context.Background(), []common.Address{usdcVault, ethVault, noRedeem, notAVault})
+	require.NoError(t, err)
+	require.Equal(t, ec.Head.Number, snapshot.BlockNumber)
+	require.Equal(t, ec.Head.Hash(), snapshot.BlockHash)
+
+	newVault := func(address common.Address, decimals uint8, sharePrice, redeemPrice int64) Vault {
+		return Vault{
+			Address:     address,
+			Asset:       asset,
+			Decimals:    decimals,
+			TotalAssets: big.NewInt(1100),
+			TotalSupply: big.NewInt(1000),
+			SharePrice:  big.NewInt(sharePrice),
+			RedeemPrice: big.NewInt(redeemPrice),
+			MaxDeposit:  big.NewInt(500),
+			Success:     true,
+		}
+	}
+
+	// the share prices are read for one share of the vault's decimals
+	ethVaultState := newVault(ethVault, 18, 0, 0)
+	ethVaultState.SharePrice, _ = new(big.Int).SetString("1100000000000000000", 10)
+	ethVaultState.RedeemPrice, _ = new(big.Int).SetString("1050000000000000000", 10)
+
+	noRedeemState := newVault(noRedeem, 6, 1_100_000, 0)
+	noRedeemState.RedeemPrice = nil
+	noRedeemState.Success = false
+
+	require.Equal(t, []Vault{
+		newVault(usdcVault, 6, 1_100_000, 1_050_000),
+		ethVaultState,
+		noRedeemState,
+		{Address: notAVault},
+	}, snapshot.Vaults)
+}
@@ -9,6 +9,20 @@ var (
 	ErrMethodNotSupported = errors.New("method not supported")
 	ErrWrongCallParam     = errors.New("wrong call param")
 	ErrUnexpectedResponse = errors.New("unexpected response")
+	ErrRPCClientNotSet    = errors.New("rpc client not set")
+	ErrBlockHashNotSet    = errors.New("block hash not set")
+	ErrInvalidProof       = errors.New("invalid proof")
+	ErrEventNotFound      = errors.New("event not found")
+	ErrInvalidEventFilter = errors.New("invalid event filter")
+	ErrReorgTooDeep       = errors.New("reorg deeper than the followed history")
+	ErrSubscriptionClosed = errors.New("subscription closed")
+
+	ErrPendingBlockNotPinnable = errors.New("pending block can not be pinned to its hash")
+	ErrUnsupportedBlockTag     = errors.New("unsupported block tag")
+	ErrCheckpointStoreNotSet   = errors.New("checkpoint store not set")
+
+	ErrStorageVariableNotFound = errors.New("storage variable not found")
+	ErrInvalidStoragePath      = errors.New("invalid storage path")
 )
 
 type UnPackMulticallError struct {
@@ -2,19 +2,27 @@ package ethrpc
 
 import (
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 // New method creates a new RPC client.
 func New(url string) *Client {
-	ec, err := ethclient.Dial(url)
+	rc, err := rpc.Dial(url)
 	if err != nil {
 		panic(err)
 	}
 
-	return createClient(ec)
+	return NewWithRPCClient(rc)
 }
 
 // NewWithClient method creates a new RPC client with given `ethclient.Client`.
+// Requests which need the raw JSON-RPC client, such as batch requests, require setting it with SetRPCClient.
 func NewWithClient(ec EthClient) *Client {
 	return createClient(ec)
 }
+
+// NewWithRPCClient method creates a new RPC client with given `rpc.Client`,
+// which is also used for the requests not covered by `ethclient.Client`, such as batch requests.
+func NewWithRPCClient(rc *rpc.Client) *Client {
+	return createClient(ethclient.NewClient(rc)).SetRPCClient(rc)
+}
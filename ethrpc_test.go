@@ -1,4 +1,4 @@
-package ethrpc
+package ethrpc_test
 
 import (
 	"context"
@@ -8,17 +8,20 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/suite"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/pools"
 )
 
 type RPCTestSuite struct {
 	suite.Suite
 
-	client *Client
+	client *ethrpc.Client
 }
 
 func (ts *RPCTestSuite) SetupTest() {
 	// Setup RPC server
-	rpcClient := New("https://eth.llamarpc.com")
+	rpcClient := ethrpc.New("https://eth.llamarpc.com")
 	rpcClient.SetMulticallContract(common.HexToAddress("0x5ba1e12693dc8f9c48aad8770482f4739beed696"))
 
 	ts.client = rpcClient
@@ -32,28 +35,20 @@ func (ts *RPCTestSuite) TestGetBlockNumber() {
 }
 
 func (ts *RPCTestSuite) TestTryAggregate() {
-	type TradeInfo struct {
-		Reserve0       *big.Int
-		Reserve1       *big.Int
-		VReserve0      *big.Int
-		VReserve1      *big.Int
-		FeeInPrecision *big.Int
-	}
-
-	pools := []string{
+	dmmPools := []string{
 		"0x9a56f30ff04884cb06da80cb3aef09c6132f5e77",
 		"0x5ba740fcc020d5b9e39760cbd2fe236586b9dc0a",
 		"0x1cf68bbc2b6d3c6cfe1bd3590cf0e10b06a05f17",
 	}
 
-	reserves := make([]TradeInfo, len(pools))
+	reserves := make([]pools.TradeInfo, len(dmmPools))
 	req := ts.client.NewRequest()
 
-	for i, p := range pools {
-		req.AddCall(&Call{
-			ABI:    dmmPoolABI,
+	for i, p := range dmmPools {
+		req.AddCall(&ethrpc.Call{
+			ABI:    pools.DmmPoolABI,
 			Target: p,
-			Method: "getTradeInfo",
+			Method: pools.MethodGetTradeInfo,
 			Params: nil,
 		}, []interface{}{&reserves[i]})
 	}
@@ -67,28 +62,20 @@ func (ts *RPCTestSuite) TestTryAggregate() {
 }
 
 func (ts *RPCTestSuite) TestTryBlockAggregate() {
-	type TradeInfo struct {
-		Reserve0       *big.Int
-		Reserve1       *big.Int
-		VReserve0      *big.Int
-		VReserve1      *big.Int
-		FeeInPrecision *big.Int
-	}
-
-	pools := []string{
+	dmmPools := []string{
 		"0x9a56f30ff04884cb06da80cb3aef09c6132f5e77",
 		"0x5ba740fcc020d5b9e39760cbd2fe236586b9dc0a",
 		"0x1cf68bbc2b6d3c6cfe1bd3590cf0e10b06a05f17",
 	}
 
-	reserves := make([]TradeInfo, len(pools))
+	reserves := make([]pools.TradeInfo, len(dmmPools))
 	req := ts.client.NewRequest()
 
-	for i, p := range pools {
-		req.AddCall(&Call{
-			ABI:    dmmPoolABI,
+	for i, p := range dmmPools {
+		req.AddCall(&ethrpc.Call{
+			ABI:    pools.DmmPoolABI,
 			Target: p,
-			Method: "getTradeInfo",
+			Method: pools.MethodGetTradeInfo,
 			Params: nil,
 		}, []interface{}{&reserves[i]})
 	}
@@ -102,6 +89,100 @@ func (ts *RPCTestSuite) TestTryBlockAggregate() {
 	//ts.Require().Len(res.Result, len(req.Calls))
 }
 
+func (ts *RPCTestSuite) TestTryBlockAggregatePinned() {
+	dmmPools := []string{
+		"0x9a56f30ff04884cb06da80cb3aef09c6132f5e77",
+		"0x5ba740fcc020d5b9e39760cbd2fe236586b9dc0a",
+		"0x1cf68bbc2b6d3c6cfe1bd3590cf0e10b06a05f17",
+	}
+
+	reserves := make([]pools.TradeInfo, len(dmmPools))
+	req := ts.client.NewRequest()
+
+	for i, p := range dmmPools {
+		req.AddCall(&ethrpc.Call{
+			ABI:    pools.DmmPoolABI,
+			Target: p,
+			Method: pools.MethodGetTradeInfo,
+			Params: nil,
+		}, []interface{}{&reserves[i]})
+	}
+
+	header, err := ts.client.HeaderByNumber(context.Background(), nil)
+	ts.Require().NoError(err)
+
+	res, err := req.SetWithBlockTimestamp(true).SetBlockHash(header.Hash()).TryBlockAndAggregate()
+
+	ts.Require().NoError(err)
+	ts.Require().Equal(header.Hash(), res.BlockHash)
+	ts.Require().Equal(header.Number, res.BlockNumber)
+	ts.Require().Equal(header.Time, res.BlockTimestamp)
+	ts.Require().Len(res.Result, len(req.Calls))
+}
+
+func (ts *RPCTestSuite) TestBatch() {
+	dmmPools := []string{
+		"0x9a56f30ff04884cb06da80cb3aef09c6132f5e77",
+		"0x5ba740fcc020d5b9e39760cbd2fe236586b9dc0a",
+		"0x1cf68bbc2b6d3c6cfe1bd3590cf0e10b06a05f17",
+	}
+
+	reserves := make([]pools.TradeInfo, len(dmmPools))
+	req := ts.client.NewRequest()
+
+	for i, p := range dmmPools {
+		req.AddCall(&ethrpc.Call{
+			ABI:    pools.DmmPoolABI,
+			Target: p,
+			Method: pools.MethodGetTradeInfo,
+			Params: nil,
+		}, []interface{}{&reserves[i]})
+	}
+
+	res, err := req.Batch()
+
+	ts.Require().NoError(err)
+	ts.Require().Len(res.Result, len(req.Calls))
+	ts.Require().Len(res.Errors, len(req.Calls))
+}
+
+func (ts *RPCTestSuite) TestBlockEnvironment() {
+	var (
+		blockNumber *big.Int
+		timestamp   *big.Int
+		coinbase    common.Address
+		lastHash    common.Hash
+		balance     *big.Int
+	)
+
+	res, err := ts.client.NewRequest().
+		AddGetBlockNumber(&blockNumber).
+		AddGetCurrentBlockTimestamp(&timestamp).
+		AddGetCurrentBlockCoinbase(&coinbase).
+		AddGetLastBlockHash(&lastHash).
+		AddGetEthBalance(common.HexToAddress("0x5ba1e12693dc8f9c48aad8770482f4739beed696"), &balance).
+		TryBlockAndAggregate()
+
+	ts.Require().NoError(err)
+	ts.Require().Equal(res.BlockNumber, blockNumber)
+	ts.Require().NotZero(timestamp.Uint64())
+	ts.Require().NotEqual(common.Hash{}, lastHash)
+	ts.Require().NotNil(balance)
+}
+
+func (ts *RPCTestSuite) TestBalancesAt() {
+	accounts := []common.Address{
+		common.HexToAddress("0x9a56f30ff04884cb06da80cb3aef09c6132f5e77"),
+		common.HexToAddress("0x5ba740fcc020d5b9e39760cbd2fe236586b9dc0a"),
+		common.HexToAddress("0x1cf68bbc2b6d3c6cfe1bd3590cf0e10b06a05f17"),
+	}
+
+	balances, err := ts.client.SetMulticallChunkSize(2).BalancesAt(context.Background(), accounts, nil)
+
+	ts.Require().NoError(err)
+	ts.Require().Len(balances, len(accounts))
+}
+
 func TestRPCTestSuite(t *testing.T) {
 	suite.Run(t, new(RPCTestSuite))
 }
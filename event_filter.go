@@ -0,0 +1,119 @@
+package ethrpc
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EventFilterBuilder builds a logs query for an event of an ABI.
+// Errors are reported by Query.
+type EventFilterBuilder struct {
+	event     abi.Event
+	query     ethereum.FilterQuery
+	arguments map[int][]interface{}
+	err       error
+}
+
+// EventFilter starts building a logs query for the event of the ABI.
+func EventFilter(contractABI abi.ABI, eventName string) *EventFilterBuilder {
+	b := &EventFilterBuilder{
+		arguments: make(map[int][]interface{}),
+	}
+
+	event, ok := contractABI.Events[eventName]
+	if !ok {
+		b.err = fmt.Errorf("%w: %s", ErrEventNotFound, eventName)
+		return b
+	}
+	b.event = event
+
+	return b
+}
+
+// Where filters the logs whose indexed parameter `name` equals one of the values.
+// Values have the Go types of the parameter, e.g. `common.Address` or `*big.Int`.
+func (b *EventFilterBuilder) Where(name string, values ...interface{}) *EventFilterBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	position := 0
+	for _, arg := range b.event.Inputs {
+		if !arg.Indexed {
+			if arg.Name == name {
+				b.err = fmt.Errorf("%w: parameter %s of %s is not indexed", ErrInvalidEventFilter, name, b.event.Name)
+				return b
+			}
+			continue
+		}
+
+		if arg.Name == name {
+			b.arguments[position] = append(b.arguments[position], values...)
+			return b
+		}
+		position++
+	}
+
+	b.err = fmt.Errorf("%w: %s has no parameter %s", ErrInvalidEventFilter, b.event.Name, name)
+
+	return b
+}
+
+// Addresses filters the logs emitted by one of the addresses.
+func (b *EventFilterBuilder) Addresses(addresses ...common.Address) *EventFilterBuilder {
+	b.query.Addresses = append(b.query.Addresses, addresses...)
+
+	return b
+}
+
+// Range filters the logs of the block range [from, to], a nil block means the latest one.
+func (b *EventFilterBuilder) Range(from, to *big.Int) *EventFilterBuilder {
+	b.query.FromBlock = from
+	b.query.ToBlock = to
+
+	return b
+}
+
+// AtBlockHash filters the logs of a single block, replacing the block range.
+func (b *EventFilterBuilder) AtBlockHash(hash common.Hash) *EventFilterBuilder {
+	b.query.BlockHash = &hash
+
+	return b
+}
+
+// Query returns the logs query, ready for FilterLogs or FetchLogs.
+func (b *EventFilterBuilder) Query() (ethereum.FilterQuery, error) {
+	if b.err != nil {
+		return ethereum.FilterQuery{}, b.err
+	}
+
+	query := b.query
+	if query.BlockHash != nil {
+		query.FromBlock, query.ToBlock = nil, nil
+	}
+
+	last := -1
+	for position := range b.arguments {
+		if position > last {
+			last = position
+		}
+	}
+
+	rules := make([][]interface{}, last+1)
+	for position, values := range b.arguments {
+		rules[position] = values
+	}
+
+	topics, err := abi.MakeTopics(rules...)
+	if err != nil {
+		return ethereum.FilterQuery{}, fmt.Errorf("%w: %v", ErrInvalidEventFilter, err)
+	}
+
+	query.Topics = append([][]common.Hash{{b.event.ID}}, topics...)
+
+	return query, nil
+}
@@ -0,0 +1,110 @@
+package ethrpc
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DecodedEvent is a log decoded with the event of an ABI
+type DecodedEvent struct {
+	Log    types.Log
+	Event  *abi.Event
+	Fields map[string]interface{}
+}
+
+// DecodeEvent decodes a log into out, a pointer to a struct or a `map[string]interface{}`,
+// using the event of the ABI matching the first topic of the log.
+// Both indexed and non-indexed fields are decoded, struct fields are matched by their camel-cased name.
+// Indexed fields of dynamic types, such as strings, are decoded as the hash of their value.
+func DecodeEvent(contractABI abi.ABI, log types.Log, out interface{}) (*abi.Event, error) {
+	if len(log.Topics) == 0 {
+		return nil, ErrEventNotFound
+	}
+
+	event, err := contractABI.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrEventNotFound, log.Topics[0])
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	nonIndexed := event.Inputs.NonIndexed()
+
+	if m, ok := out.(map[string]interface{}); ok {
+		if err = nonIndexed.UnpackIntoMap(m, log.Data); err != nil {
+			return nil, err
+		}
+		if err = abi.ParseTopicsIntoMap(m, indexed, log.Topics[1:]); err != nil {
+			return nil, err
+		}
+
+		return event, nil
+	}
+
+	values, err := nonIndexed.Unpack(log.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err = copyEventValues(out, nonIndexed, values); err != nil {
+		return nil, err
+	}
+	if err = abi.ParseTopics(out, indexed, log.Topics[1:]); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// DecodeEvents decodes the logs matching an event of the ABI into maps, skipping the other logs.
+func DecodeEvents(contractABI abi.ABI, logs []types.Log) ([]DecodedEvent, error) {
+	var events []DecodedEvent
+
+	for _, log := range logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		if _, err := contractABI.EventByID(log.Topics[0]); err != nil {
+			continue
+		}
+
+		fields := map[string]interface{}{}
+		event, err := DecodeEvent(contractABI, log, fields)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, DecodedEvent{Log: log, Event: event, Fields: fields})
+	}
+
+	return events, nil
+}
+
+// copyEventValues copies the non-indexed values into out.
+// A single value is copied into the struct field of the same name, unlike `abi.Arguments.Copy`
+// which copies it into the first field.
+func copyEventValues(out interface{}, nonIndexed abi.Arguments, values []interface{}) error {
+	if len(nonIndexed) != 1 {
+		return nonIndexed.Copy(out, values)
+	}
+
+	dst := reflect.ValueOf(out)
+	if dst.Kind() != reflect.Ptr || dst.Elem().Kind() != reflect.Struct {
+		return nonIndexed.Copy(out, values)
+	}
+
+	field := dst.Elem().FieldByName(abi.ToCamelCase(nonIndexed[0].Name))
+	src := reflect.ValueOf(values[0])
+	if !field.IsValid() || !field.CanSet() || !src.Type().AssignableTo(field.Type()) {
+		return fmt.Errorf("%w: can not decode %s into %s", ErrUnexpectedResponse, nonIndexed[0].Name, dst.Elem().Type())
+	}
+	field.Set(src)
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package ethrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	from := common.HexToAddress("0x9a56f30ff04884cb06da80cb3aef09c6132f5e77")
+	to := common.HexToAddress("0x5ba740fcc020d5b9e39760cbd2fe236586b9dc0a")
+
+	transfer := dmmPoolABI.Events["Transfer"]
+	data, err := transfer.Inputs.NonIndexed().Pack(big.NewInt(42))
+	require.NoError(t, err)
+
+	log := types.Log{
+		Topics: []common.Hash{transfer.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:   data,
+	}
+
+	var out struct {
+		From  common.Address
+		To    common.Address
+		Value *big.Int
+	}
+	event, err := DecodeEvent(dmmPoolABI, log, &out)
+	require.NoError(t, err)
+	require.Equal(t, "Transfer", event.Name)
+	require.Equal(t, from, out.From)
+	require.Equal(t, to, out.To)
+	require.Equal(t, big.NewInt(42), out.Value)
+
+	fields := map[string]interface{}{}
+	_, err = DecodeEvent(dmmPoolABI, log, fields)
+	require.NoError(t, err)
+	require.Equal(t, from, fields["from"])
+	require.Equal(t, big.NewInt(42), fields["value"])
+
+	_, err = DecodeEvent(dmmPoolABI, types.Log{Topics: []common.Hash{{}}}, fields)
+	require.ErrorIs(t, err, ErrEventNotFound)
+}
+
+func TestEventFilter(t *testing.T) {
+	pool := common.HexToAddress("0x9a56f30ff04884cb06da80cb3aef09c6132f5e77")
+	to := common.HexToAddress("0x5ba740fcc020d5b9e39760cbd2fe236586b9dc0a")
+
+	query, err := EventFilter(dmmPoolABI, "Swap").
+		Where("to", to).
+		Addresses(pool).
+		Range(big.NewInt(100), big.NewInt(200)).
+		Query()
+	require.NoError(t, err)
+	require.Equal(t, []common.Address{pool}, query.Addresses)
+	require.Equal(t, big.NewInt(100), query.FromBlock)
+	require.Equal(t, big.NewInt(200), query.ToBlock)
+	require.Equal(t, [][]common.Hash{
+		{dmmPoolABI.Events["Swap"].ID},
+		nil,
+		{common.BytesToHash(to.Bytes())},
+	}, query.Topics)
+
+	_, err = EventFilter(dmmPoolABI, "Swap").Where("amount0In", big.NewInt(1)).Query()
+	require.ErrorIs(t, err, ErrInvalidEventFilter)
+
+	_, err = EventFilter(dmmPoolABI, "Unknown").Query()
+	require.ErrorIs(t, err, ErrEventNotFound)
+}
@@ -0,0 +1,268 @@
+package ethrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// DefaultFollowerHistorySize is the default number of recent blocks a ChainFollower keeps to detect reorgs
+	DefaultFollowerHistorySize = 128
+
+	// DefaultFollowerPollInterval is the default interval a ChainFollower polls for new heads
+	// when the client does not support subscriptions
+	DefaultFollowerPollInterval = 2 * time.Second
+)
+
+// BlockEventType is the type of a BlockEvent
+type BlockEventType int
+
+const (
+	// BlockAdded is sent when a block becomes part of the canonical chain
+	BlockAdded BlockEventType = iota
+	// BlockRemoved is sent when a block is orphaned by a reorg, its logs have Removed set
+	BlockRemoved
+	// BlockConfirmed is sent when a block reaches the confirmation depth
+	BlockConfirmed
+)
+
+// BlockEvent is a change of the canonical chain seen by a ChainFollower
+type BlockEvent struct {
+	Type   BlockEventType
+	Header *types.Header
+	// Logs are the logs of the block matching the follower's log query, if any
+	Logs []types.Log
+}
+
+// BlockEventHandler handles the events of a ChainFollower, in order.
+type BlockEventHandler func(ctx context.Context, event BlockEvent) error
+
+// ChainFollower follows the chain head and detects reorgs by parent hash mismatch.
+// Blocks orphaned by a reorg are notified as removed, along with their logs, before the blocks replacing them are added.
+type ChainFollower struct {
+	client        *Client
+	query         *ethereum.FilterQuery
+	confirmations uint64
+	historySize   int
+	pollInterval  time.Duration
+
+	// chain contains the recent canonical blocks, in ascending order
+	chain []*followedBlock
+	// nextConfirmed is the number of the next block to confirm
+	nextConfirmed uint64
+}
+
+type followedBlock struct {
+	header *types.Header
+	logs   []types.Log
+}
+
+// NewChainFollower creates a new ChainFollower starting at the current head.
+func (c *Client) NewChainFollower() *ChainFollower {
+	return &ChainFollower{
+		client:       c,
+		historySize:  DefaultFollowerHistorySize,
+		pollInterval: DefaultFollowerPollInterval,
+	}
+}
+
+// SetLogQuery sets the query of the logs delivered with each block, its block range is ignored.
+func (f *ChainFollower) SetLogQuery(query ethereum.FilterQuery) *ChainFollower {
+	f.query = &query
+
+	return f
+}
+
+// SetConfirmations sets the number of blocks on top of a block for it to be confirmed.
+func (f *ChainFollower) SetConfirmations(confirmations uint64) *ChainFollower {
+	f.confirmations = confirmations
+
+	return f
+}
+
+// SetHistorySize sets the number of recent blocks kept to detect reorgs.
+// Reorgs deeper than the history fail with ErrReorgTooDeep.
+func (f *ChainFollower) SetHistorySize(historySize int) *ChainFollower {
+	f.historySize = historySize
+
+	return f
+}
+
+// SetPollInterval sets the interval to poll for new heads when the client does not support subscriptions.
+func (f *ChainFollower) SetPollInterval(pollInterval time.Duration) *ChainFollower {
+	f.pollInterval = pollInterval
+
+	return f
+}
+
+// Run follows the chain until the context is done or the handler fails.
+// New heads are received with SubscribeNewHead if the client supports it, otherwise they are polled.
+func (f *ChainFollower) Run(ctx context.Context, handler BlockEventHandler) error {
+	heads := make(chan *types.Header, 16)
+
+	sub, err := f.client.ethClient.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		logger.Debugf("failed to subscribe new heads, polling instead, err: %v", err)
+		return f.poll(ctx, handler)
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err = <-sub.Err():
+			return err
+		case head := <-heads:
+			if err = f.advance(ctx, head, handler); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (f *ChainFollower) poll(ctx context.Context, handler BlockEventHandler) error {
+	ticker := time.NewTicker(f.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		head, err := f.client.ethClient.HeaderByNumber(ctx, nil)
+		if err != nil {
+			logger.Errorf("failed to get head, err: %v", err)
+			return err
+		}
+
+		if err = f.advance(ctx, head, handler); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// advance moves the followed chain to the new head, walking its ancestors back to a known block,
+// removing the blocks which are not ancestors of the new head and adding the new ones.
+func (f *ChainFollower) advance(ctx context.Context, head *types.Header, handler BlockEventHandler) error {
+	if f.tip() != nil && f.tip().header.Hash() == head.Hash() {
+		return nil
+	}
+
+	branch := []*types.Header{head}
+	fork := -1
+
+	for len(f.chain) > 0 {
+		first := branch[0]
+		if idx := f.indexOf(first.ParentHash); idx >= 0 {
+			fork = idx
+			break
+		}
+
+		if first.Number.Uint64() <= f.chain[0].header.Number.Uint64() {
+			return ErrReorgTooDeep
+		}
+
+		parent, err := f.client.ethClient.HeaderByHash(ctx, first.ParentHash)
+		if err != nil {
+			logger.Errorf("failed to get header %v, err: %v", first.ParentHash, err)
+			return err
+		}
+		branch = append([]*types.Header{parent}, branch...)
+	}
+
+	// remove the orphaned blocks, from the tip down to the fork point
+	for len(f.chain) > fork+1 {
+		b := f.chain[len(f.chain)-1]
+		f.chain = f.chain[:len(f.chain)-1]
+		if number := b.header.Number.Uint64(); number < f.nextConfirmed {
+			f.nextConfirmed = number
+		}
+
+		removed := make([]types.Log, len(b.logs))
+		for i, l := range b.logs {
+			l.Removed = true
+			removed[i] = l
+		}
+
+		if err := handler(ctx, BlockEvent{Type: BlockRemoved, Header: b.header, Logs: removed}); err != nil {
+			return err
+		}
+	}
+
+	for _, header := range branch {
+		b := &followedBlock{header: header}
+
+		if f.query != nil {
+			query := *f.query
+			hash := header.Hash()
+			query.FromBlock, query.ToBlock, query.BlockHash = nil, nil, &hash
+
+			logs, err := f.client.ethClient.FilterLogs(ctx, query)
+			if err != nil {
+				logger.Errorf("failed to get logs of %v, err: %v", hash, err)
+				return err
+			}
+			b.logs = logs
+		}
+
+		f.chain = append(f.chain, b)
+		if err := handler(ctx, BlockEvent{Type: BlockAdded, Header: header, Logs: b.logs}); err != nil {
+			return err
+		}
+	}
+
+	if err := f.confirm(ctx, handler); err != nil {
+		return err
+	}
+
+	if len(f.chain) > f.historySize && f.historySize > 0 {
+		f.chain = f.chain[len(f.chain)-f.historySize:]
+	}
+
+	return nil
+}
+
+// confirm notifies the blocks which reached the confirmation depth.
+func (f *ChainFollower) confirm(ctx context.Context, handler BlockEventHandler) error {
+	tip := f.tip().header.Number.Uint64()
+
+	for _, b := range f.chain {
+		number := b.header.Number.Uint64()
+		if number < f.nextConfirmed || number+f.confirmations > tip {
+			continue
+		}
+
+		if err := handler(ctx, BlockEvent{Type: BlockConfirmed, Header: b.header, Logs: b.logs}); err != nil {
+			return err
+		}
+		f.nextConfirmed = number + 1
+	}
+
+	return nil
+}
+
+func (f *ChainFollower) tip() *followedBlock {
+	if len(f.chain) == 0 {
+		return nil
+	}
+
+	return f.chain[len(f.chain)-1]
+}
+
+func (f *ChainFollower) indexOf(hash common.Hash) int {
+	for i := len(f.chain) - 1; i >= 0; i-- {
+		if f.chain[i].header.Hash() == hash {
+			return i
+		}
+	}
+
+	return -1
+}
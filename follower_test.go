@@ -0,0 +1,114 @@
+package ethrpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// chainEthClient serves the headers it knows by hash, with one log per block
+type chainEthClient struct {
+	EthClient
+
+	headers map[common.Hash]*types.Header
+}
+
+func (c *chainEthClient) HeaderByHash(_ context.Context, hash common.Hash) (*types.Header, error) {
+	return c.headers[hash], nil
+}
+
+func (c *chainEthClient) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	return []types.Log{{BlockHash: *q.BlockHash, BlockNumber: c.headers[*q.BlockHash].Number.Uint64()}}, nil
+}
+
+// extend builds a branch of n blocks on top of parent
+func (c *chainEthClient) extend(parent *types.Header, n int, fork byte) []*types.Header {
+	var branch []*types.Header
+	for i := 0; i < n; i++ {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Extra:      []byte{fork},
+		}
+		c.headers[header.Hash()] = header
+		branch = append(branch, header)
+		parent = header
+	}
+
+	return branch
+}
+
+func TestChainFollowerReorg(t *testing.T) {
+	ec := &chainEthClient{headers: map[common.Hash]*types.Header{}}
+	genesis := &types.Header{Number: big.NewInt(0)}
+	ec.headers[genesis.Hash()] = genesis
+	a := ec.extend(genesis, 4, 'a')
+	b := ec.extend(a[0], 4, 'b')
+
+	var events []BlockEvent
+	handler := func(_ context.Context, event BlockEvent) error {
+		events = append(events, event)
+		return nil
+	}
+	f := NewWithClient(ec).NewChainFollower().SetLogQuery(ethereum.FilterQuery{}).SetConfirmations(2)
+
+	ctx := context.Background()
+	require.NoError(t, f.advance(ctx, genesis, handler))
+	require.NoError(t, f.advance(ctx, a[3], handler))
+
+	type event struct {
+		Type   BlockEventType
+		Number uint64
+		Fork   string
+	}
+	summarize := func() []event {
+		var out []event
+		for _, e := range events {
+			out = append(out, event{e.Type, e.Header.Number.Uint64(), string(e.Header.Extra)})
+			for _, l := range e.Logs {
+				require.Equal(t, e.Header.Hash(), l.BlockHash)
+				require.Equal(t, e.Type == BlockRemoved, l.Removed)
+			}
+		}
+		events = nil
+		return out
+	}
+	require.Equal(t, []event{
+		{BlockAdded, 0, ""},
+		{BlockAdded, 1, "a"},
+		{BlockAdded, 2, "a"},
+		{BlockAdded, 3, "a"},
+		{BlockAdded, 4, "a"},
+		{BlockConfirmed, 0, ""},
+		{BlockConfirmed, 1, "a"},
+		{BlockConfirmed, 2, "a"},
+	}, summarize())
+
+	// b forks off a[0], orphaning blocks 2 to 4 including the confirmed block 2
+	require.NoError(t, f.advance(ctx, b[3], handler))
+	require.Equal(t, []event{
+		{BlockRemoved, 4, "a"},
+		{BlockRemoved, 3, "a"},
+		{BlockRemoved, 2, "a"},
+		{BlockAdded, 2, "b"},
+		{BlockAdded, 3, "b"},
+		{BlockAdded, 4, "b"},
+		{BlockAdded, 5, "b"},
+		{BlockConfirmed, 2, "b"},
+		{BlockConfirmed, 3, "b"},
+	}, summarize())
+
+	// the same head again is a no-op
+	require.NoError(t, f.advance(ctx, b[3], handler))
+	require.Empty(t, events)
+
+	f.SetHistorySize(2)
+	require.NoError(t, f.advance(ctx, ec.extend(b[3], 1, 'b')[0], handler))
+	summarize()
+	require.ErrorIs(t, f.advance(ctx, ec.extend(b[0], 1, 'c')[0], handler), ErrReorgTooDeep)
+}
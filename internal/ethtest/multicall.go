@@ -0,0 +1,178 @@
+// Package ethtest provides a fake ethrpc.EthClient executing multicalls with Go functions, for offline tests.
+package ethtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/abis"
+)
+
+var multicallABI abi.ABI
+
+func init() {
+	var err error
+	if multicallABI, err = abi.JSON(bytes.NewReader(abis.Multicall)); err != nil {
+		panic(err)
+	}
+}
+
+// call is a call of a multicall
+type call struct {
+	Target   common.Address
+	CallData []byte
+}
+
+// CallFunc executes a call of a multicall at the given block, an error makes the call revert.
+type CallFunc func(block ethrpc.Block, target common.Address, data []byte) ([]byte, error)
+
+// Multicall is a fake EthClient serving `aggregate`, `tryAggregate` and `tryBlockAndAggregate`
+// by executing each call with its CallFunc, which also executes single calls of other methods.
+// `getCurrentBlockTimestamp` calls return the time of the block's header.
+// Calls at the latest block are executed at Head, which may be moved forward to mine blocks.
+// Methods other than the ones below panic.
+type Multicall struct {
+	ethrpc.EthClient
+
+	Head  *types.Header
+	Chain *big.Int
+	Call  CallFunc
+
+	// served contains the headers returned so far, by hash
+	served map[common.Hash]*types.Header
+}
+
+// NewMulticall creates a new Multicall at block 1 of chain 1.
+func NewMulticall(call CallFunc) *Multicall {
+	return &Multicall{
+		Head:  &types.Header{Number: big.NewInt(1)},
+		Chain: big.NewInt(1),
+		Call:  call,
+	}
+}
+
+func (m *Multicall) ChainID(context.Context) (*big.Int, error) {
+	return m.Chain, nil
+}
+
+func (m *Multicall) BlockNumber(context.Context) (uint64, error) {
+	return m.Head.Number.Uint64(), nil
+}
+
+func (m *Multicall) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if number != nil && number.Cmp(m.Head.Number) != 0 {
+		return nil, ethereum.NotFound
+	}
+
+	return m.serve(m.Head), nil
+}
+
+func (m *Multicall) HeaderByHash(_ context.Context, hash common.Hash) (*types.Header, error) {
+	if header, ok := m.served[hash]; ok {
+		return header, nil
+	}
+	if hash != m.Head.Hash() {
+		return nil, ethereum.NotFound
+	}
+
+	return m.serve(m.Head), nil
+}
+
+func (m *Multicall) serve(header *types.Header) *types.Header {
+	if m.served == nil {
+		m.served = make(map[common.Hash]*types.Header)
+	}
+	m.served[header.Hash()] = header
+
+	return header
+}
+
+func (m *Multicall) CallContract(_ context.Context, msg ethereum.CallMsg, number *big.Int) ([]byte, error) {
+	if number == nil {
+		number = m.Head.Number
+	}
+
+	return m.multicall(ethrpc.AtBlockNumber(number), msg)
+}
+
+func (m *Multicall) CallContractAtHash(_ context.Context, msg ethereum.CallMsg, hash common.Hash) ([]byte, error) {
+	return m.multicall(ethrpc.AtBlockHash(hash, false), msg)
+}
+
+// multicall executes the multicall of the message, or the message itself with Call if it's not a multicall.
+func (m *Multicall) multicall(block ethrpc.Block, msg ethereum.CallMsg) ([]byte, error) {
+	data := msg.Data
+	if len(data) < 4 {
+		return nil, errors.New("invalid call data")
+	}
+
+	method, err := multicallABI.MethodById(data[:4])
+	if err != nil {
+		return m.Call(block, *msg.To, data)
+	}
+
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	// the calls are the last argument of all multicall methods
+	calls := *abi.ConvertType(args[len(args)-1], new([]call)).(*[]call)
+
+	results := make(ethrpc.TryAggregateResult, len(calls))
+	for i, call := range calls {
+		var returnData []byte
+		if bytes.HasPrefix(call.CallData, multicallABI.Methods[ethrpc.MethodGetCurrentBlockTimestamp].ID) {
+			returnData, err = multicallABI.Methods[ethrpc.MethodGetCurrentBlockTimestamp].Outputs.Pack(m.blockTimestamp(block))
+		} else {
+			returnData, err = m.Call(block, call.Target, call.CallData)
+		}
+		results[i] = ethrpc.TryAggregateResultItem{Success: err == nil, ReturnData: returnData}
+	}
+
+	switch method.Name {
+	case ethrpc.MethodAggregate:
+		returnData := make([][]byte, len(results))
+		for i, r := range results {
+			if !r.Success {
+				return nil, errors.New("execution reverted")
+			}
+			returnData[i] = r.ReturnData
+		}
+
+		return method.Outputs.Pack(m.blockNumber(block), returnData)
+	case ethrpc.MethodTryAggregate:
+		return method.Outputs.Pack(results)
+	case ethrpc.MethodTryBlockAndAggregate:
+		return method.Outputs.Pack(m.blockNumber(block), [32]byte{}, results)
+	default:
+		return nil, errors.New("unsupported method " + method.Name)
+	}
+}
+
+func (m *Multicall) blockNumber(block ethrpc.Block) *big.Int {
+	if block.Number != nil {
+		return block.Number
+	}
+	if header, ok := m.served[block.Hash]; ok {
+		return header.Number
+	}
+
+	return m.Head.Number
+}
+
+func (m *Multicall) blockTimestamp(block ethrpc.Block) *big.Int {
+	if header, ok := m.served[block.Hash]; ok {
+		return new(big.Int).SetUint64(header.Time)
+	}
+
+	return new(big.Int).SetUint64(m.Head.Time)
+}
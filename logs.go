@@ -0,0 +1,244 @@
+package ethrpc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// DefaultLogWindowSize is the default number of blocks queried by one `eth_getLogs` in FetchLogs
+	DefaultLogWindowSize = 2000
+
+	// DefaultLogConcurrency is the default number of windows queried concurrently in FetchLogs
+	DefaultLogConcurrency = 4
+)
+
+// logRangeErrors are parts of the errors returned by nodes when a logs query is too large,
+// in which case the block range is bisected.
+var logRangeErrors = []string{
+	"more than 10000 results",
+	"query returned more than",
+	"block range too large",
+	"block range is too wide",
+	"exceed maximum block range",
+	"range too large",
+	"response size exceeded",
+	"response size should not greater than",
+	"log response size exceeded",
+	"query timeout exceeded",
+}
+
+// SetLogWindowSize sets the number of blocks queried by one `eth_getLogs` in FetchLogs.
+func (c *Client) SetLogWindowSize(windowSize uint64) *Client {
+	c.logWindowSize = windowSize
+
+	return c
+}
+
+// SetLogConcurrency sets the number of windows queried concurrently in FetchLogs.
+func (c *Client) SetLogConcurrency(concurrency int) *Client {
+	c.logConcurrency = concurrency
+
+	return c
+}
+
+// FetchLogs fetches the logs matching the query, splitting its block range into windows of the log window size
+// which are fetched concurrently. Windows are bisected when the node reports that they have too many results
+// or that their range is too large. A nil ToBlock means the latest block. The range may use the latest, safe
+// and finalized tags of `rpc.BlockNumber`, which are resolved to block numbers first, but not the pending one.
+// Logs are returned sorted by block number and log index.
+func (c *Client) FetchLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if query.BlockHash != nil {
+		return c.ethClient.FilterLogs(ctx, query)
+	}
+
+	from := uint64(0)
+	if query.FromBlock != nil {
+		var err error
+		if from, err = c.resolveLogBlock(ctx, query.FromBlock); err != nil {
+			return nil, err
+		}
+	}
+
+	to, err := c.resolveLogBlock(ctx, query.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	if from > to {
+		return nil, nil
+	}
+
+	windowSize := c.logWindowSize
+	if windowSize == 0 {
+		windowSize = to - from + 1
+	}
+
+	type window struct {
+		from, to uint64
+		logs     []types.Log
+	}
+
+	var windows []*window
+	for start := from; start <= to; start += windowSize {
+		end := start + windowSize - 1
+		if end > to || end < start {
+			end = to
+		}
+		windows = append(windows, &window{from: start, to: end})
+
+		if end == to {
+			break
+		}
+	}
+
+	concurrency := c.logConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		sem      = make(chan struct{}, concurrency)
+	)
+
+loop:
+	for _, w := range windows {
+		// stop starting windows once one of them failed
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break loop
+		}
+		if ctx.Err() != nil {
+			<-sem
+			break loop
+		}
+
+		wg.Add(1)
+
+		go func(w *window) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			logs, err := c.fetchLogsRange(ctx, query, w.from, w.to)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			w.logs = logs
+		}(w)
+	}
+	wg.Wait()
+
+	if firstErr == nil {
+		// the parent context is done
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		logger.Errorf("failed to fetch logs [%d, %d], err: %v", from, to, firstErr)
+		return nil, firstErr
+	}
+
+	var logs []types.Log
+	for _, w := range windows {
+		logs = append(logs, w.logs...)
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	return logs, nil
+}
+
+// resolveLogBlock resolves a block number of a log query to a positive number, nil being the latest block.
+func (c *Client) resolveLogBlock(ctx context.Context, number *big.Int) (uint64, error) {
+	if number != nil && number.Sign() >= 0 {
+		return number.Uint64(), nil
+	}
+
+	if number == nil || number.Int64() == int64(rpc.LatestBlockNumber) {
+		latest, err := c.ethClient.BlockNumber(ctx)
+		if err != nil {
+			logger.Errorf("failed to get block number, err: %v", err)
+			return 0, err
+		}
+
+		return latest, nil
+	}
+
+	if number.Int64() != int64(rpc.SafeBlockNumber) && number.Int64() != int64(rpc.FinalizedBlockNumber) {
+		return 0, fmt.Errorf("%w: %v", ErrUnsupportedBlockTag, rpc.BlockNumber(number.Int64()))
+	}
+
+	header, err := c.ethClient.HeaderByNumber(ctx, number)
+	if err != nil {
+		logger.Errorf("failed to get %v header, err: %v", rpc.BlockNumber(number.Int64()), err)
+		return 0, err
+	}
+
+	return header.Number.Uint64(), nil
+}
+
+// fetchLogsRange fetches the logs of the block range [from, to], bisecting it when it's too large.
+func (c *Client) fetchLogsRange(ctx context.Context, query ethereum.FilterQuery, from, to uint64) ([]types.Log, error) {
+	query.FromBlock = new(big.Int).SetUint64(from)
+	query.ToBlock = new(big.Int).SetUint64(to)
+
+	logs, err := c.ethClient.FilterLogs(ctx, query)
+	if err == nil {
+		return logs, nil
+	}
+
+	if from == to || !isLogRangeError(err) {
+		return nil, err
+	}
+
+	mid := from + (to-from)/2
+	left, err := c.fetchLogsRange(ctx, query, from, mid)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := c.fetchLogsRange(ctx, query, mid+1, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(left, right...), nil
+}
+
+// isLogRangeError tells whether the error means that a logs query is too large.
+func isLogRangeError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, e := range logRangeErrors {
+		if strings.Contains(msg, e) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,114 @@
+package ethrpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// logsEthClient serves one DMM pool Transfer log per block up to its head, transferring the block number,
+// and fails queries returning more than maxResults logs, or every query when err is set. Its finalized block
+// is 10 blocks behind its head
+type logsEthClient struct {
+	EthClient
+
+	mu         sync.Mutex
+	head       uint64
+	maxResults uint64
+	err        error
+	queries    int
+}
+
+func (c *logsEthClient) BlockNumber(context.Context) (uint64, error) {
+	return c.head, nil
+}
+
+func (c *logsEthClient) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if number.Int64() != int64(rpc.FinalizedBlockNumber) {
+		return nil, errors.New("unexpected block number")
+	}
+
+	return &types.Header{Number: new(big.Int).SetUint64(c.head - 10)}, nil
+}
+
+func (c *logsEthClient) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	c.queries++
+	c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	from, to := q.FromBlock.Uint64(), q.ToBlock.Uint64()
+	if to-from+1 > c.maxResults {
+		return nil, errors.New("query returned more than 10000 results")
+	}
+
+	transfer := dmmPoolABI.Events["Transfer"]
+	var logs []types.Log
+	for b := from; b <= to; b++ {
+		data, err := transfer.Inputs.NonIndexed().Pack(new(big.Int).SetUint64(b))
+		if err != nil {
+			return nil, err
+		}
+
+		logs = append(logs, types.Log{
+			Topics:      []common.Hash{transfer.ID, {}, {}},
+			Data:        data,
+			BlockNumber: b,
+			Index:       uint(b),
+		})
+	}
+
+	return logs, nil
+}
+
+func TestFetchLogs(t *testing.T) {
+	ec := &logsEthClient{head: 99, maxResults: 7}
+	client := NewWithClient(ec).SetLogWindowSize(30).SetLogConcurrency(3)
+
+	logs, err := client.FetchLogs(context.Background(), ethereum.FilterQuery{})
+	require.NoError(t, err)
+	require.Len(t, logs, 100)
+	for i, l := range logs {
+		require.Equal(t, uint64(i), l.BlockNumber)
+	}
+	require.Greater(t, ec.queries, 4)
+}
+
+func TestFetchLogsBlockTags(t *testing.T) {
+	ec := &logsEthClient{head: 99, maxResults: 100}
+	client := NewWithClient(ec)
+
+	logs, err := client.FetchLogs(context.Background(), ethereum.FilterQuery{
+		FromBlock: big.NewInt(int64(rpc.FinalizedBlockNumber)),
+		ToBlock:   big.NewInt(int64(rpc.LatestBlockNumber)),
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 11)
+	require.Equal(t, uint64(89), logs[0].BlockNumber)
+	require.Equal(t, uint64(99), logs[10].BlockNumber)
+
+	_, err = client.FetchLogs(context.Background(), ethereum.FilterQuery{
+		ToBlock: big.NewInt(int64(rpc.PendingBlockNumber)),
+	})
+	require.ErrorIs(t, err, ErrUnsupportedBlockTag)
+}
+
+func TestFetchLogsStopsAfterError(t *testing.T) {
+	fetchErr := errors.New("connection refused")
+	ec := &logsEthClient{head: 99, maxResults: 100, err: fetchErr}
+	client := NewWithClient(ec).SetLogWindowSize(10).SetLogConcurrency(1)
+
+	_, err := client.FetchLogs(context.Background(), ethereum.FilterQuery{})
+	require.ErrorIs(t, err, fetchErr)
+	require.Equal(t, 1, ec.queries)
+}
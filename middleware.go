@@ -1,6 +1,8 @@
 package ethrpc
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 
@@ -28,21 +30,9 @@ func parseRequestCallParam(c *Client, req *Request) error {
 
 		return nil
 	case MethodAggregate:
-		var multiCallParams []MultiCallParam
-
-		for _, c := range req.Calls {
-			callData, err := c.ABI.Pack(c.Method, c.Params...)
-			if err != nil {
-				logger.Errorf("failed to build call data for target=%s method=%s, err: %v", c.Target, c.Method, err)
-				return err
-			}
-
-			multiCallParams = append(
-				multiCallParams, MultiCallParam{
-					Target:   common.HexToAddress(c.Target),
-					CallData: callData,
-				},
-			)
+		multiCallParams, err := buildMultiCallParams(c, req)
+		if err != nil {
+			return err
 		}
 
 		callData, err := multicallABI.Pack(MethodAggregate, multiCallParams)
@@ -56,21 +46,9 @@ func parseRequestCallParam(c *Client, req *Request) error {
 
 		return nil
 	case MethodTryAggregate:
-		var multiCallParams []MultiCallParam
-
-		for _, call := range req.Calls {
-			callData, err := call.ABI.Pack(call.Method, call.Params...)
-			if err != nil {
-				logger.Errorf("failed to build call data for target=%s method=%s, err: %v", call.Target, call.Method, err)
-				return err
-			}
-
-			multiCallParams = append(
-				multiCallParams, MultiCallParam{
-					Target:   common.HexToAddress(call.Target),
-					CallData: callData,
-				},
-			)
+		multiCallParams, err := buildMultiCallParams(c, req)
+		if err != nil {
+			return err
 		}
 
 		callData, err := multicallABI.Pack(MethodTryAggregate, req.RequireSuccess, multiCallParams)
@@ -95,21 +73,9 @@ func parseRequestCallParam(c *Client, req *Request) error {
 
 		return nil
 	case MethodTryBlockAndAggregate:
-		var multiCallParams []MultiCallParam
-
-		for _, call := range req.Calls {
-			callData, err := call.ABI.Pack(call.Method, call.Params...)
-			if err != nil {
-				logger.Errorf("failed to build call data for target=%s method=%s, err: %v", call.Target, call.Method, err)
-				return err
-			}
-
-			multiCallParams = append(
-				multiCallParams, MultiCallParam{
-					Target:   common.HexToAddress(call.Target),
-					CallData: callData,
-				},
-			)
+		multiCallParams, err := buildMultiCallParams(c, req)
+		if err != nil {
+			return err
 		}
 
 		callData, err := multicallABI.Pack(MethodTryBlockAndAggregate, req.RequireSuccess, multiCallParams)
@@ -121,12 +87,76 @@ func parseRequestCallParam(c *Client, req *Request) error {
 		msg := ethereum.CallMsg{To: &c.multiCallContract, Data: callData}
 		req.RawCallMsg = msg
 
+		return nil
+	case MethodBatch:
+		req.RawCallMsgs = make([]ethereum.CallMsg, 0, len(req.Calls))
+
+		for _, call := range req.Calls {
+			callData, err := call.ABI.Pack(call.Method, call.Params...)
+			if err != nil {
+				logger.Errorf("failed to build call data for target=%s method=%s, err: %v", call.Target, call.Method, err)
+				return err
+			}
+
+			target := common.HexToAddress(call.Target)
+			req.RawCallMsgs = append(req.RawCallMsgs, ethereum.CallMsg{To: &target, Data: callData})
+		}
+
 		return nil
 	default:
 		return ErrMethodNotSupported
 	}
 }
 
+// buildMultiCallParams packs the request's calls into multicall params.
+// If the request asks for the block timestamp, a `getCurrentBlockTimestamp` call is appended.
+func buildMultiCallParams(c *Client, req *Request) ([]MultiCallParam, error) {
+	multiCallParams := make([]MultiCallParam, 0, req.multiCallSize())
+
+	for _, call := range req.Calls {
+		callData, err := call.ABI.Pack(call.Method, call.Params...)
+		if err != nil {
+			logger.Errorf("failed to build call data for target=%s method=%s, err: %v", call.Target, call.Method, err)
+			return nil, err
+		}
+
+		multiCallParams = append(
+			multiCallParams, MultiCallParam{
+				Target:   common.HexToAddress(call.Target),
+				CallData: callData,
+			},
+		)
+	}
+
+	if req.WithBlockTimestamp {
+		callData, err := multicallABI.Pack(MethodGetCurrentBlockTimestamp)
+		if err != nil {
+			logger.Errorf("failed to build call data, err: %v", err)
+			return nil, err
+		}
+
+		multiCallParams = append(
+			multiCallParams, MultiCallParam{
+				Target:   c.multiCallContract,
+				CallData: callData,
+			},
+		)
+	}
+
+	return multiCallParams, nil
+}
+
+// unpackBlockTimestamp unpacks the returned data of a `getCurrentBlockTimestamp` call.
+func unpackBlockTimestamp(data []byte) (uint64, error) {
+	var timestamp *big.Int
+	if err := multicallABI.UnpackIntoInterface(&timestamp, MethodGetCurrentBlockTimestamp, data); err != nil {
+		logger.Errorf("failed to unpack block timestamp, err: %v", err)
+		return 0, err
+	}
+
+	return timestamp.Uint64(), nil
+}
+
 func parseResponse(_ *Client, res *Response) (err error) {
 	switch res.Request.Method {
 	case MethodCall:
@@ -146,11 +176,17 @@ func parseResponse(_ *Client, res *Response) (err error) {
 		var result AggregateResult
 
 		err = multicallABI.UnpackIntoInterface(&result, res.Request.Method, res.RawResponse)
-		if err != nil || len(result.ReturnData) != len(res.Request.Calls) {
+		if err != nil || len(result.ReturnData) != res.Request.multiCallSize() {
 			logger.Errorf("failed to unpack aggregate response, err: %v", err)
 			return err
 		}
 
+		if res.Request.WithBlockTimestamp {
+			if res.BlockTimestamp, err = unpackBlockTimestamp(result.ReturnData[len(res.Request.Calls)]); err != nil {
+				return err
+			}
+		}
+
 		for i, c := range res.Request.Calls {
 			// result will always be true if it can reach this far
 			res.Result = append(res.Result, true)
@@ -168,27 +204,28 @@ func parseResponse(_ *Client, res *Response) (err error) {
 		var result TryAggregateResult
 
 		err = multicallABI.UnpackIntoInterface(&result, res.Request.Method, res.RawResponse)
-		if err != nil || len(result) != len(res.Request.Calls) {
+		if err != nil || len(result) != res.Request.multiCallSize() {
 			logger.Errorf("failed to unpack tryAggregate response, err: %v", err)
 			return err
 		}
 
+		if res.Request.WithBlockTimestamp && result[len(res.Request.Calls)].Success {
+			if res.BlockTimestamp, err = unpackBlockTimestamp(result[len(res.Request.Calls)].ReturnData); err != nil {
+				return err
+			}
+		}
+
 		for i, c := range res.Request.Calls {
 			res.Result = append(res.Result, result[i].Success)
 
 			if result[i].Success {
-				for j, unpackABI := range c.UnpackABI {
-					if err = unpackABI.UnpackIntoInterface(c.Output[j], c.Method, result[i].ReturnData); err == nil {
-						break
-					}
-
-					if j == len(c.UnpackABI)-1 {
-						logger.Errorf("failed to unpack target=%s method=%s, err: %v", c.Target, c.Method, err)
+				if err = c.unpack(result[i].ReturnData); err != nil {
+					logger.Errorf("failed to unpack target=%s method=%s, err: %v", c.Target, c.Method, err)
 
-						if res.Request.RequireSuccess {
-							return NewUnPackMulticallError(err)
-						}
+					if res.Request.RequireSuccess {
+						return NewUnPackMulticallError(err)
 					}
+					res.Result[i] = false
 				}
 			}
 		}
@@ -202,31 +239,65 @@ func parseResponse(_ *Client, res *Response) (err error) {
 		var result TryBlockAndAggregateResult
 
 		err = multicallABI.UnpackIntoInterface(&result, res.Request.Method, res.RawResponse)
-		if err != nil || len(result.ReturnData) != len(res.Request.Calls) {
+		if err != nil || len(result.ReturnData) != res.Request.multiCallSize() {
 			logger.Errorf("failed to unpack tryAggregate response, err: %v", err)
 			return err
 		}
 
+		if res.Request.WithBlockTimestamp && result.ReturnData[len(res.Request.Calls)].Success {
+			if res.BlockTimestamp, err = unpackBlockTimestamp(result.ReturnData[len(res.Request.Calls)].ReturnData); err != nil {
+				return err
+			}
+		}
+
 		for i, c := range res.Request.Calls {
 			res.Result = append(res.Result, result.ReturnData[i].Success)
 
 			if result.ReturnData[i].Success {
-				for j, unpackABI := range c.UnpackABI {
-					if err = unpackABI.UnpackIntoInterface(c.Output[j], c.Method, result.ReturnData[i].ReturnData); err == nil {
-						break
-					}
-
-					if j == len(c.UnpackABI)-1 {
-						logger.Errorf("failed to unpack target=%s method=%s, err: %v", c.Target, c.Method, err)
+				if err = c.unpack(result.ReturnData[i].ReturnData); err != nil {
+					logger.Errorf("failed to unpack target=%s method=%s, err: %v", c.Target, c.Method, err)
 
-						if res.Request.RequireSuccess {
-							return NewUnPackMulticallError(err)
-						}
+					if res.Request.RequireSuccess {
+						return NewUnPackMulticallError(err)
 					}
+					res.Result[i] = false
 				}
 			}
 		}
 		res.BlockNumber = result.BlockNumber
+		// the multicall returns `blockhash(block.number)` which is always zero,
+		// so the hash is only known when the request is pinned to it
+		res.BlockHash = res.Request.BlockHash
+
+		return nil
+	case MethodBatch:
+		if len(res.RawResponses) != len(res.Request.Calls) || len(res.Errors) != len(res.Request.Calls) {
+			return ErrUnexpectedResponse
+		}
+
+		for i, c := range res.Request.Calls {
+			if res.Errors[i] != nil {
+				logger.Errorf("failed to call target=%s method=%s, err: %v", c.Target, c.Method, res.Errors[i])
+				res.Result = append(res.Result, false)
+
+				if res.Request.RequireSuccess {
+					return res.Errors[i]
+				}
+
+				continue
+			}
+
+			res.Result = append(res.Result, true)
+
+			if err = c.unpack(res.RawResponses[i]); err != nil {
+				logger.Errorf("failed to unpack target=%s method=%s, err: %v", c.Target, c.Method, err)
+
+				if res.Request.RequireSuccess {
+					return NewUnPackMulticallError(err)
+				}
+				res.Result[i] = false
+			}
+		}
 
 		return nil
 	default:
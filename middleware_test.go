@@ -0,0 +1,50 @@
+package ethrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResponseUnpackFailure(t *testing.T) {
+	value := common.BigToHash(big.NewInt(7)).Bytes()
+	results := TryAggregateResult{{Success: true, ReturnData: value}, {Success: true, ReturnData: nil}}
+
+	tryAggregate, err := multicallABI.Methods[MethodTryAggregate].Outputs.Pack(results)
+	require.NoError(t, err)
+	tryBlockAndAggregate, err := multicallABI.Methods[MethodTryBlockAndAggregate].Outputs.Pack(big.NewInt(1), [32]byte{}, results)
+	require.NoError(t, err)
+
+	responses := map[string]*Response{
+		MethodTryAggregate:         {RawResponse: tryAggregate},
+		MethodTryBlockAndAggregate: {RawResponse: tryBlockAndAggregate},
+		MethodBatch:                {RawResponses: [][]byte{value, nil}, Errors: []error{nil, nil}},
+	}
+
+	for method, res := range responses {
+		for _, requireSuccess := range []bool{false, true} {
+			var ok, empty *big.Int
+			req := NewWithClient(nil).R().SetRequireSuccess(requireSuccess)
+			for _, output := range []**big.Int{&ok, &empty} {
+				req.AddCall(&Call{ABI: dmmPoolABI, Target: common.Address{}.Hex(), Method: "totalSupply"}, []interface{}{output})
+			}
+			req.Method = method
+
+			res := *res
+			res.Request = req
+			err := parseResponse(nil, &res)
+
+			// a successful call returning no data can't be unpacked into its output
+			if requireSuccess {
+				require.Error(t, err, method)
+				continue
+			}
+			require.NoError(t, err, method)
+			require.Equal(t, []bool{true, false}, res.Result, method)
+			require.Equal(t, big.NewInt(7), ok, method)
+			require.Nil(t, empty, method)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package ethrpc
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Multicall contract methods which read the block environment
+const (
+	multicallMethodGetBlockHash              = "getBlockHash"
+	multicallMethodGetBlockNumber            = "getBlockNumber"
+	multicallMethodGetCurrentBlockCoinbase   = "getCurrentBlockCoinbase"
+	multicallMethodGetCurrentBlockDifficulty = "getCurrentBlockDifficulty"
+	multicallMethodGetCurrentBlockGasLimit   = "getCurrentBlockGasLimit"
+	multicallMethodGetCurrentBlockTimestamp  = MethodGetCurrentBlockTimestamp
+	multicallMethodGetEthBalance             = "getEthBalance"
+	multicallMethodGetLastBlockHash          = "getLastBlockHash"
+)
+
+// addMulticallCall adds a call to one of the multicall contract's own methods.
+func (r *Request) addMulticallCall(method string, params []interface{}, output interface{}) *Request {
+	return r.AddCall(&Call{
+		ABI:    multicallABI,
+		Target: r.client.multiCallContract.Hex(),
+		Method: method,
+		Params: params,
+	}, []interface{}{output})
+}
+
+// AddGetBlockHash adds a call reading the hash of the given block, one of the 256 most recent blocks.
+func (r *Request) AddGetBlockHash(blockNumber *big.Int, output *common.Hash) *Request {
+	return r.addMulticallCall(multicallMethodGetBlockHash, []interface{}{blockNumber}, output)
+}
+
+// AddGetBlockNumber adds a call reading the current block number.
+func (r *Request) AddGetBlockNumber(output **big.Int) *Request {
+	return r.addMulticallCall(multicallMethodGetBlockNumber, nil, output)
+}
+
+// AddGetCurrentBlockCoinbase adds a call reading the current block's coinbase.
+func (r *Request) AddGetCurrentBlockCoinbase(output *common.Address) *Request {
+	return r.addMulticallCall(multicallMethodGetCurrentBlockCoinbase, nil, output)
+}
+
+// AddGetCurrentBlockDifficulty adds a call reading the current block's difficulty,
+// which is the `prevrandao` value after the merge.
+func (r *Request) AddGetCurrentBlockDifficulty(output **big.Int) *Request {
+	return r.addMulticallCall(multicallMethodGetCurrentBlockDifficulty, nil, output)
+}
+
+// AddGetCurrentBlockGasLimit adds a call reading the current block's gas limit.
+func (r *Request) AddGetCurrentBlockGasLimit(output **big.Int) *Request {
+	return r.addMulticallCall(multicallMethodGetCurrentBlockGasLimit, nil, output)
+}
+
+// AddGetCurrentBlockTimestamp adds a call reading the current block's timestamp.
+func (r *Request) AddGetCurrentBlockTimestamp(output **big.Int) *Request {
+	return r.addMulticallCall(multicallMethodGetCurrentBlockTimestamp, nil, output)
+}
+
+// AddGetLastBlockHash adds a call reading the hash of the previous block.
+func (r *Request) AddGetLastBlockHash(output *common.Hash) *Request {
+	return r.addMulticallCall(multicallMethodGetLastBlockHash, nil, output)
+}
+
+// AddGetEthBalance adds a call reading the native balance of the given account.
+func (r *Request) AddGetEthBalance(account common.Address, output **big.Int) *Request {
+	return r.addMulticallCall(multicallMethodGetEthBalance, []interface{}{account}, output)
+}
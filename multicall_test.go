@@ -0,0 +1,78 @@
+package ethrpc_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/internal/ethtest"
+)
+
+func TestTryBlockAndAggregateBlockHash(t *testing.T) {
+	ec := ethtest.NewMulticall(func(ethrpc.Block, common.Address, []byte) ([]byte, error) {
+		return nil, nil
+	})
+	client := ethrpc.NewWithClient(ec)
+
+	var hash common.Hash
+	res, err := client.R().AddGetBlockHash(ec.Head.Number, &hash).SetBlockHash(ec.Head.Hash()).TryBlockAndAggregate()
+	require.NoError(t, err)
+	require.Equal(t, ec.Head.Hash(), res.BlockHash)
+	require.Equal(t, ec.Head.Number, res.BlockNumber)
+
+	// the multicall can't read the hash of the block it's executed in
+	res, err = client.R().AddGetBlockHash(ec.Head.Number, &hash).TryBlockAndAggregate()
+	require.NoError(t, err)
+	require.Equal(t, common.Hash{}, res.BlockHash)
+}
+
+func TestExecuteInChunksPinsBlock(t *testing.T) {
+	var blocks []ethrpc.Block
+	ec := ethtest.NewMulticall(nil)
+	head := ec.Head
+	ec.Call = func(block ethrpc.Block, _ common.Address, _ []byte) ([]byte, error) {
+		blocks = append(blocks, block)
+		// a new block is mined after each call
+		ec.Head = &types.Header{Number: new(big.Int).Add(ec.Head.Number, common.Big1)}
+		return nil, nil
+	}
+
+	req := ethrpc.NewWithClient(ec).SetMulticallChunkSize(2).R()
+	var hash common.Hash
+	for i := 0; i < 5; i++ {
+		req.AddGetBlockHash(big.NewInt(int64(i)), &hash)
+	}
+
+	res, err := req.TryAggregateInChunks()
+	require.NoError(t, err)
+	require.Len(t, res.Result, 5)
+	require.Len(t, blocks, 5)
+	for _, block := range blocks {
+		require.Equal(t, ethrpc.AtBlockHash(head.Hash(), false), block)
+	}
+}
+
+func TestPinBlock(t *testing.T) {
+	ec := ethtest.NewMulticall(nil)
+	client := ethrpc.NewWithClient(ec)
+
+	block, header, err := client.PinBlock(context.Background(), ethrpc.Block{})
+	require.NoError(t, err)
+	require.Equal(t, ethrpc.AtBlockHash(ec.Head.Hash(), false), block)
+	require.Equal(t, ec.Head.Number, header.Number)
+
+	// a block selected by its hash is kept, along with RequireCanonical
+	canonical := ethrpc.AtBlockHash(ec.Head.Hash(), true)
+	block, header, err = client.PinBlock(context.Background(), canonical)
+	require.NoError(t, err)
+	require.Equal(t, canonical, block)
+	require.Equal(t, ec.Head.Hash(), header.Hash())
+
+	_, _, err = client.PinBlock(context.Background(), ethrpc.AtBlockTag(ethrpc.BlockTagPending))
+	require.ErrorIs(t, err, ethrpc.ErrPendingBlockNotPinnable)
+}
@@ -0,0 +1,35 @@
+package nft
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	"github.com/PandaRR007/ethrpc/abis"
+)
+
+var (
+	// ERC721ABI is the ABI of ERC-721 tokens
+	ERC721ABI abi.ABI
+
+	// ERC1155ABI is the ABI of ERC-1155 tokens
+	ERC1155ABI abi.ABI
+)
+
+func init() {
+	builder := []struct {
+		ABI  *abi.ABI
+		data []byte
+	}{
+		{&ERC721ABI, abis.ERC721},
+		{&ERC1155ABI, abis.ERC1155},
+	}
+
+	for _, b := range builder {
+		var err error
+		*b.ABI, err = abi.JSON(bytes.NewReader(b.data))
+		if err != nil {
+			panic(err)
+		}
+	}
+}
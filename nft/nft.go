@@ -0,0 +1,314 @@
+// Package nft reads ERC-721 and ERC-1155 tokens in batches through the multicall contract of an ethrpc.Client,
+// detecting the token standard with ERC-165 in the same multicall.
+package nft
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/PandaRR007/ethrpc"
+)
+
+const (
+	MethodBalanceOf         = "balanceOf"
+	MethodBalanceOfBatch    = "balanceOfBatch"
+	MethodOwnerOf           = "ownerOf"
+	MethodSupportsInterface = "supportsInterface"
+	MethodTokenURI          = "tokenURI"
+	MethodURI               = "uri"
+)
+
+// Standard is the token standard of a contract
+type Standard string
+
+const (
+	StandardUnknown Standard = ""
+	StandardERC721  Standard = "ERC721"
+	StandardERC1155 Standard = "ERC1155"
+)
+
+// ERC-165 interface IDs of the token standards
+var (
+	InterfaceIDERC721  = [4]byte{0x80, 0xac, 0x58, 0xcd}
+	InterfaceIDERC1155 = [4]byte{0xd9, 0xb6, 0x7a, 0x26}
+)
+
+// Token is a token ID of a contract
+type Token struct {
+	Contract common.Address
+	TokenID  *big.Int
+}
+
+// TokenData is the data of a token
+type TokenData struct {
+	Token
+	Standard Standard
+	// Owner is only set for ERC-721 tokens
+	Owner common.Address
+	// URI is the token URI, with the `{id}` of ERC-1155 URIs substituted
+	URI string
+	// Success is false if the calls of the token's standard failed,
+	// which happens for burned or nonexistent ERC-721 tokens
+	Success bool
+}
+
+// BalanceQuery is an owner of tokens of a contract.
+// TokenID is required for ERC-1155 contracts and ignored for ERC-721 ones.
+type BalanceQuery struct {
+	Contract common.Address
+	Owner    common.Address
+	TokenID  *big.Int
+}
+
+// Balance is the balance of a query, nil if it could not be read
+type Balance struct {
+	BalanceQuery
+	Standard Standard
+	Balance  *big.Int
+}
+
+// Reader reads tokens in batches using `tryAggregate`, so that a failing token does not fail the others.
+type Reader struct {
+	client *ethrpc.Client
+	block  ethrpc.Block
+}
+
+// NewReader creates a new Reader using the client's multicall contract.
+func NewReader(client *ethrpc.Client) *Reader {
+	return &Reader{
+		client: client,
+	}
+}
+
+// SetBlock pins the block all reads are executed at.
+func (r *Reader) SetBlock(block ethrpc.Block) *Reader {
+	r.block = block
+
+	return r
+}
+
+// GetTokens reads the standard, owner and URI of the tokens.
+func (r *Reader) GetTokens(ctx context.Context, tokens []Token) ([]TokenData, error) {
+	type output struct {
+		owner                           common.Address
+		tokenURI, uri                   string
+		ownerOfIdx, tokenURIIdx, uriIdx int
+	}
+
+	req := r.newRequest(ctx)
+	standards := addStandardCalls(req, contracts(len(tokens), func(i int) common.Address { return tokens[i].Contract }))
+
+	outputs := make([]output, len(tokens))
+	for i, t := range tokens {
+		o := &outputs[i]
+		o.ownerOfIdx = addCall(req, ERC721ABI, t.Contract, MethodOwnerOf, &o.owner, t.TokenID)
+		o.tokenURIIdx = addCall(req, ERC721ABI, t.Contract, MethodTokenURI, &o.tokenURI, t.TokenID)
+		o.uriIdx = addCall(req, ERC1155ABI, t.Contract, MethodURI, &o.uri, t.TokenID)
+	}
+
+	res, err := req.TryAggregateInChunks()
+	if err != nil {
+		logger.Errorf("failed to get tokens, err: %v", err)
+		return nil, err
+	}
+
+	result := make([]TokenData, len(tokens))
+	for i, t := range tokens {
+		o := outputs[i]
+		data := TokenData{
+			Token:    t,
+			Standard: orERC721(standards[t.Contract].standard(res), res.Result[o.ownerOfIdx]),
+		}
+
+		switch data.Standard {
+		case StandardERC721:
+			data.Owner = o.owner
+			data.URI = o.tokenURI
+			data.Success = res.Result[o.ownerOfIdx] && o.owner != (common.Address{})
+		case StandardERC1155:
+			data.URI = substituteID(o.uri, t.TokenID)
+			data.Success = res.Result[o.uriIdx]
+		}
+
+		result[i] = data
+	}
+
+	return result, nil
+}
+
+// GetBalances reads the balances of the queries.
+// Unlike GetTokens, it detects the standards of the contracts in a first multicall and then only makes
+// the calls of each query's standard in a second one, both pinned to the same block, see ethrpc.Client.PinBlock.
+// This costs a round trip, but avoids sending an ERC-721 `balanceOf` and an ERC-1155 `balanceOfBatch` for
+// every query. Balances of ERC-1155 tokens are read with one `balanceOfBatch` call per contract,
+// and contracts without ERC-165 are read as ERC-721 ones.
+func (r *Reader) GetBalances(ctx context.Context, queries []BalanceQuery) ([]Balance, error) {
+	type batch struct {
+		owners   []common.Address
+		ids      []*big.Int
+		balances []*big.Int
+		idx      int
+	}
+
+	block, _, err := r.client.PinBlock(ctx, r.block)
+	if err != nil {
+		return nil, err
+	}
+
+	req := r.client.NewRequest().SetContext(ctx).SetBlock(block)
+	standardCalls := addStandardCalls(req, contracts(len(queries), func(i int) common.Address { return queries[i].Contract }))
+
+	res, err := req.TryAggregateInChunks()
+	if err != nil {
+		logger.Errorf("failed to get standards, err: %v", err)
+		return nil, err
+	}
+
+	standards := make(map[common.Address]Standard, len(standardCalls))
+	for contract, s := range standardCalls {
+		standards[contract] = s.standard(res)
+	}
+
+	req = r.client.NewRequest().SetContext(ctx).SetBlock(block)
+
+	erc721Balances := make([]*big.Int, len(queries))
+	erc721Idx := make([]int, len(queries))
+	batchIdx := make([]int, len(queries))
+	batches := make(map[common.Address]*batch)
+
+	for i, q := range queries {
+		erc721Idx[i] = -1
+
+		if standards[q.Contract] != StandardERC1155 {
+			erc721Idx[i] = addCall(req, ERC721ABI, q.Contract, MethodBalanceOf, &erc721Balances[i], q.Owner)
+			continue
+		}
+
+		if q.TokenID == nil {
+			continue
+		}
+
+		b, ok := batches[q.Contract]
+		if !ok {
+			b = &batch{}
+			batches[q.Contract] = b
+		}
+		batchIdx[i] = len(b.owners)
+		b.owners = append(b.owners, q.Owner)
+		b.ids = append(b.ids, q.TokenID)
+	}
+
+	for contract, b := range batches {
+		b.idx = addCall(req, ERC1155ABI, contract, MethodBalanceOfBatch, &b.balances, b.owners, b.ids)
+	}
+
+	res, err = req.TryAggregateInChunks()
+	if err != nil {
+		logger.Errorf("failed to get balances, err: %v", err)
+		return nil, err
+	}
+
+	result := make([]Balance, len(queries))
+	for i, q := range queries {
+		erc721Success := erc721Idx[i] >= 0 && res.Result[erc721Idx[i]]
+		balance := Balance{BalanceQuery: q, Standard: orERC721(standards[q.Contract], erc721Success)}
+
+		switch balance.Standard {
+		case StandardERC721:
+			if erc721Success {
+				balance.Balance = erc721Balances[i]
+			}
+		case StandardERC1155:
+			if b, ok := batches[q.Contract]; ok && res.Result[b.idx] && batchIdx[i] < len(b.balances) {
+				balance.Balance = b.balances[batchIdx[i]]
+			}
+		}
+
+		result[i] = balance
+	}
+
+	return result, nil
+}
+
+func (r *Reader) newRequest(ctx context.Context) *ethrpc.Request {
+	return r.client.NewRequest().SetContext(ctx).SetBlock(r.block)
+}
+
+// orERC721 falls back to ERC-721 for contracts without ERC-165 whose ERC-721 call succeeded.
+func orERC721(standard Standard, erc721Success bool) Standard {
+	if standard == StandardUnknown && erc721Success {
+		return StandardERC721
+	}
+
+	return standard
+}
+
+// standardOutput is the ERC-165 detection of a contract
+type standardOutput struct {
+	erc721, erc1155       bool
+	erc721Idx, erc1155Idx int
+}
+
+func (s *standardOutput) standard(res *ethrpc.Response) Standard {
+	switch {
+	case res.Result[s.erc721Idx] && s.erc721:
+		return StandardERC721
+	case res.Result[s.erc1155Idx] && s.erc1155:
+		return StandardERC1155
+	default:
+		return StandardUnknown
+	}
+}
+
+// addStandardCalls adds the ERC-165 calls detecting the standard of each contract.
+func addStandardCalls(req *ethrpc.Request, contracts []common.Address) map[common.Address]*standardOutput {
+	standards := make(map[common.Address]*standardOutput, len(contracts))
+
+	for _, contract := range contracts {
+		s := &standardOutput{}
+		s.erc721Idx = addCall(req, ERC721ABI, contract, MethodSupportsInterface, &s.erc721, InterfaceIDERC721)
+		s.erc1155Idx = addCall(req, ERC721ABI, contract, MethodSupportsInterface, &s.erc1155, InterfaceIDERC1155)
+		standards[contract] = s
+	}
+
+	return standards
+}
+
+// contracts returns the distinct contracts of n items.
+func contracts(n int, contract func(i int) common.Address) []common.Address {
+	seen := make(map[common.Address]struct{})
+	var result []common.Address
+
+	for i := 0; i < n; i++ {
+		c := contract(i)
+		if _, ok := seen[c]; !ok {
+			seen[c] = struct{}{}
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// addCall adds a call to the request and returns its index.
+func addCall(req *ethrpc.Request, contractABI abi.ABI, contract common.Address, method string, output interface{}, params ...interface{}) int {
+	req.AddCall(&ethrpc.Call{
+		ABI:    contractABI,
+		Target: contract.Hex(),
+		Method: method,
+		Params: params,
+	}, []interface{}{output})
+
+	return len(req.Calls) - 1
+}
+
+// substituteID substitutes the `{id}` of an ERC-1155 URI with the lowercase hex token ID padded to 64 characters.
+func substituteID(uri string, id *big.Int) string {
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+}
@@ -0,0 +1,128 @@
+package nft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/internal/ethtest"
+)
+
+var (
+	erc721   = common.HexToAddress("0xbc4ca0eda7647a8ab7c2061c2e118a18a936f13d")
+	erc1155  = common.HexToAddress("0x76be3b62873462d2142405439777e971754e8e77")
+	legacy   = common.HexToAddress("0x06012c8cf97bead5deae237070f9587f8e7a266d")
+	holder   = common.HexToAddress("0x000000000000000000000000000000000000beef")
+	revertTx = errors.New("execution reverted")
+)
+
+// burned is the ID of the burned ERC-721 tokens
+const burned = 8
+
+// tokenID unpacks the token ID of a call taking it as its only argument
+func tokenID(method *abi.Method, data []byte) int64 {
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return -1
+	}
+
+	return args[0].(*big.Int).Int64()
+}
+
+// newNFTClient serves an ERC-721 contract, an ERC-1155 one and an ERC-721 one without ERC-165, each owning
+// balances of 1, 2 and 3 to the holder, and records the calls made to each contract. ERC-721 tokens are
+// owned by the holder, except the burned one. Calls of methods the contract does not implement revert.
+func newNFTClient(calls map[common.Address][]string) *ethrpc.Client {
+	return ethrpc.NewWithClient(ethtest.NewMulticall(func(_ ethrpc.Block, target common.Address, data []byte) ([]byte, error) {
+		method, err := ERC721ABI.MethodById(data[:4])
+		if err != nil {
+			if method, err = ERC1155ABI.MethodById(data[:4]); err != nil {
+				return nil, revertTx
+			}
+		}
+		calls[target] = append(calls[target], method.Name)
+
+		switch {
+		case method.Name == MethodSupportsInterface && target != legacy:
+			id := data[4:8]
+			supported := target == erc721 && [4]byte(id) == InterfaceIDERC721 ||
+				target == erc1155 && [4]byte(id) == InterfaceIDERC1155
+			return method.Outputs.Pack(supported)
+		case method.Name == MethodBalanceOf && target == erc721:
+			return method.Outputs.Pack(big.NewInt(1))
+		case method.Name == MethodBalanceOf && target == legacy:
+			return method.Outputs.Pack(big.NewInt(3))
+		case method.Name == MethodBalanceOfBatch && target == erc1155:
+			return method.Outputs.Pack([]*big.Int{big.NewInt(2)})
+		case method.Name == MethodOwnerOf && target != erc1155 && tokenID(method, data) == burned:
+			return nil, revertTx
+		case method.Name == MethodOwnerOf && target != erc1155:
+			return method.Outputs.Pack(holder)
+		case method.Name == MethodTokenURI && target != erc1155:
+			return method.Outputs.Pack(fmt.Sprintf("ipfs://tokens/%d", tokenID(method, data)))
+		case method.Name == MethodURI && target == erc1155:
+			return method.Outputs.Pack("https://tokens/{id}.json")
+		default:
+			return nil, revertTx
+		}
+	}))
+}
+
+func TestGetBalances(t *testing.T) {
+	calls := make(map[common.Address][]string)
+	reader := NewReader(newNFTClient(calls))
+
+	queries := []BalanceQuery{
+		{Contract: erc721, Owner: holder, TokenID: big.NewInt(7)},
+		{Contract: erc1155, Owner: holder, TokenID: big.NewInt(7)},
+		{Contract: legacy, Owner: holder},
+	}
+
+	balances, err := reader.GetBalances(context.Background(), queries)
+	require.NoError(t, err)
+	require.Equal(t, []Balance{
+		{BalanceQuery: queries[0], Standard: StandardERC721, Balance: big.NewInt(1)},
+		{BalanceQuery: queries[1], Standard: StandardERC1155, Balance: big.NewInt(2)},
+		{BalanceQuery: queries[2], Standard: StandardERC721, Balance: big.NewInt(3)},
+	}, balances)
+
+	// only the calls of each contract's standard are made
+	require.Equal(t, map[common.Address][]string{
+		erc721:  {MethodSupportsInterface, MethodSupportsInterface, MethodBalanceOf},
+		erc1155: {MethodSupportsInterface, MethodSupportsInterface, MethodBalanceOfBatch},
+		legacy:  {MethodSupportsInterface, MethodSupportsInterface, MethodBalanceOf},
+	}, calls)
+}
+
+func TestGetTokens(t *testing.T) {
+	reader := NewReader(newNFTClient(make(map[common.Address][]string)))
+
+	tokens := []Token{
+		{Contract: erc721, TokenID: big.NewInt(7)},
+		{Contract: erc721, TokenID: big.NewInt(burned)},
+		{Contract: erc1155, TokenID: big.NewInt(255)},
+		{Contract: legacy, TokenID: big.NewInt(7)},
+	}
+
+	data, err := reader.GetTokens(context.Background(), tokens)
+	require.NoError(t, err)
+	require.Equal(t, []TokenData{
+		{Token: tokens[0], Standard: StandardERC721, Owner: holder, URI: "ipfs://tokens/7", Success: true},
+		{Token: tokens[1], Standard: StandardERC721, URI: "ipfs://tokens/8"},
+		{
+			Token:    tokens[2],
+			Standard: StandardERC1155,
+			URI:      "https://tokens/00000000000000000000000000000000000000000000000000000000000000ff.json",
+			Success:  true,
+		},
+		// detected as ERC-721 as `ownerOf` succeeded
+		{Token: tokens[3], Standard: StandardERC721, Owner: holder, URI: "ipfs://tokens/7", Success: true},
+	}, data)
+}
@@ -0,0 +1,86 @@
+package ethrpc
+
+import (
+	"context"
+
+	"github.com/KyberNetwork/logger"
+)
+
+// Stage adds the calls of a pipeline stage to the request.
+// It is called after all previous stages are executed, so it can build its calls from their decoded outputs.
+// A stage which adds no call is skipped.
+type Stage func(req *Request) error
+
+// Pipeline executes stages of dependent calls, each stage being chunked and multicalled with `tryAggregate`.
+// All stages are executed at the same block.
+type Pipeline struct {
+	client *Client
+	ctx    context.Context
+	block  Block
+	stages []Stage
+}
+
+// NewPipeline creates a new pipeline of dependent calls.
+func (c *Client) NewPipeline() *Pipeline {
+	return &Pipeline{
+		client: c,
+	}
+}
+
+// SetContext sets the context of all the stages' requests.
+func (p *Pipeline) SetContext(ctx context.Context) *Pipeline {
+	p.ctx = ctx
+
+	return p
+}
+
+// SetBlock sets the block all the stages are executed at.
+// The pipeline is pinned to the hash of the block, the latest one if it's not set, when it's executed,
+// see Client.PinBlock.
+func (p *Pipeline) SetBlock(block Block) *Pipeline {
+	p.block = block
+
+	return p
+}
+
+// Then appends a stage to the pipeline.
+func (p *Pipeline) Then(stage Stage) *Pipeline {
+	p.stages = append(p.stages, stage)
+
+	return p
+}
+
+// Execute executes the stages in order, and returns the response of each stage.
+// The response of a skipped stage is nil.
+func (p *Pipeline) Execute() ([]*Response, error) {
+	ctx := p.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	block, _, err := p.client.PinBlock(ctx, p.block)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*Response, len(p.stages))
+	for i, stage := range p.stages {
+		req := p.client.R().SetContext(ctx).SetBlock(block)
+		if err := stage(req); err != nil {
+			return nil, err
+		}
+
+		if len(req.Calls) == 0 {
+			continue
+		}
+
+		res, err := req.TryAggregateInChunks()
+		if err != nil {
+			logger.Errorf("failed to execute pipeline stage %d, err: %v", i, err)
+			return nil, err
+		}
+		responses[i] = res
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,120 @@
+package ethrpc_test
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/internal/ethtest"
+)
+
+const collectionABIJson = `[
+	{"type":"function","name":"length","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"items","stateMutability":"view","inputs":[{"name":"index","type":"uint256"}],"outputs":[{"name":"","type":"address"}]},
+	{"type":"function","name":"owner","stateMutability":"view","inputs":[{"name":"item","type":"address"}],"outputs":[{"name":"","type":"address"}]}
+]`
+
+var (
+	collectionABI = mustParseABI(collectionABIJson)
+	collection    = common.HexToAddress("0x5c69bee701ef814a2b6a3edd4b1652cb9cc5aa6f")
+)
+
+func mustParseABI(data string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(data))
+	if err != nil {
+		panic(err)
+	}
+
+	return parsed
+}
+
+// newCollection serves a collection of length items, item i being the address i+1, owned by the address
+// of the item plus 100. It records the block of each call.
+func newCollection(length int64, blocks *[]ethrpc.Block) *ethtest.Multicall {
+	return ethtest.NewMulticall(func(block ethrpc.Block, _ common.Address, data []byte) ([]byte, error) {
+		*blocks = append(*blocks, block)
+
+		method, err := collectionABI.MethodById(data[:4])
+		if err != nil {
+			return nil, err
+		}
+		args, err := method.Inputs.Unpack(data[4:])
+		if err != nil {
+			return nil, err
+		}
+
+		switch method.Name {
+		case "length":
+			return method.Outputs.Pack(big.NewInt(length))
+		case "items":
+			index := args[0].(*big.Int)
+			if index.Int64() >= length {
+				return nil, errors.New("execution reverted")
+			}
+			return method.Outputs.Pack(common.BigToAddress(new(big.Int).Add(index, common.Big1)))
+		default:
+			item := args[0].(common.Address).Big()
+			return method.Outputs.Pack(common.BigToAddress(item.Add(item, big.NewInt(100))))
+		}
+	})
+}
+
+func TestPipeline(t *testing.T) {
+	var blocks []ethrpc.Block
+	ec := newCollection(2, &blocks)
+
+	var (
+		items  = make([]common.Address, 2)
+		owners = make([]common.Address, 2)
+	)
+	responses, err := ethrpc.NewWithClient(ec).NewPipeline().
+		Then(func(req *ethrpc.Request) error {
+			for i := range items {
+				req.AddCall(&ethrpc.Call{
+					ABI:    collectionABI,
+					Target: collection.Hex(),
+					Method: "items",
+					Params: []interface{}{big.NewInt(int64(i))},
+				}, []interface{}{&items[i]})
+			}
+
+			return nil
+		}).
+		// a stage adding no call is skipped
+		Then(func(*ethrpc.Request) error {
+			return nil
+		}).
+		Then(func(req *ethrpc.Request) error {
+			for i, item := range items {
+				req.AddCall(&ethrpc.Call{
+					ABI:    collectionABI,
+					Target: collection.Hex(),
+					Method: "owner",
+					Params: []interface{}{item},
+				}, []interface{}{&owners[i]})
+			}
+
+			return nil
+		}).
+		Execute()
+	require.NoError(t, err)
+
+	require.Len(t, responses, 3)
+	require.Equal(t, []bool{true, true}, responses[0].Result)
+	require.Nil(t, responses[1])
+	require.Equal(t, []bool{true, true}, responses[2].Result)
+
+	require.Equal(t, []common.Address{common.BigToAddress(big.NewInt(101)), common.BigToAddress(big.NewInt(102))}, owners)
+
+	// all the stages are executed at the same block
+	require.Len(t, blocks, 4)
+	for _, block := range blocks {
+		require.Equal(t, ethrpc.AtBlockHash(ec.Head.Hash(), false), block)
+	}
+}
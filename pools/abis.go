@@ -0,0 +1,39 @@
+package pools
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+
+	"github.com/PandaRR007/ethrpc/abis"
+)
+
+var (
+	// DmmPoolABI is the ABI of Kyber DMM pools
+	DmmPoolABI abi.ABI
+
+	// UniswapV2PairABI is the ABI of Uniswap V2 pairs
+	UniswapV2PairABI abi.ABI
+
+	// UniswapV3PoolABI is the ABI of Uniswap V3 pools
+	UniswapV3PoolABI abi.ABI
+)
+
+func init() {
+	builder := []struct {
+		ABI  *abi.ABI
+		data []byte
+	}{
+		{&DmmPoolABI, abis.DmmPool},
+		{&UniswapV2PairABI, abis.UniswapV2Pair},
+		{&UniswapV3PoolABI, abis.UniswapV3Pool},
+	}
+
+	for _, b := range builder {
+		var err error
+		*b.ABI, err = abi.JSON(bytes.NewReader(b.data))
+		if err != nil {
+			panic(err)
+		}
+	}
+}
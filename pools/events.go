@@ -0,0 +1,58 @@
+package pools
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Events of DMM pools, to be decoded from logs with ethrpc.DecodeEvent and DmmPoolABI
+const (
+	EventBurn      = "Burn"
+	EventMint      = "Mint"
+	EventSwap      = "Swap"
+	EventSync      = "Sync"
+	EventUpdateEMA = "UpdateEMA"
+)
+
+// DmmSync is the `Sync` event of a DMM pool
+type DmmSync struct {
+	VReserve0 *big.Int
+	VReserve1 *big.Int
+	Reserve0  *big.Int
+	Reserve1  *big.Int
+}
+
+// DmmSwap is the `Swap` event of a DMM pool
+type DmmSwap struct {
+	Sender         common.Address
+	Amount0In      *big.Int
+	Amount1In      *big.Int
+	Amount0Out     *big.Int
+	Amount1Out     *big.Int
+	To             common.Address
+	FeeInPrecision *big.Int
+}
+
+// DmmMint is the `Mint` event of a DMM pool
+type DmmMint struct {
+	Sender  common.Address
+	Amount0 *big.Int
+	Amount1 *big.Int
+}
+
+// DmmBurn is the `Burn` event of a DMM pool
+type DmmBurn struct {
+	Sender  common.Address
+	Amount0 *big.Int
+	Amount1 *big.Int
+	To      common.Address
+}
+
+// DmmUpdateEMA is the `UpdateEMA` event of a DMM pool
+type DmmUpdateEMA struct {
+	ShortEMA        *big.Int
+	LongEMA         *big.Int
+	LastBlockVolume *big.Int
+	SkipBlock       *big.Int
+}
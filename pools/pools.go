@@ -0,0 +1,177 @@
+// Package pools fetches the state of AMM pools in bulk through the multicall contract of an ethrpc.Client.
+package pools
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/PandaRR007/ethrpc"
+)
+
+const (
+	MethodAmpBps       = "ampBps"
+	MethodGetReserves  = "getReserves"
+	MethodGetTradeInfo = "getTradeInfo"
+	MethodKLast        = "kLast"
+	MethodToken0       = "token0"
+	MethodToken1       = "token1"
+)
+
+// TradeInfo is the output of a DMM pool's `getTradeInfo`
+type TradeInfo struct {
+	Reserve0       *big.Int
+	Reserve1       *big.Int
+	VReserve0      *big.Int
+	VReserve1      *big.Int
+	FeeInPrecision *big.Int
+}
+
+// DmmPool is the state of a Kyber DMM pool
+type DmmPool struct {
+	Address common.Address
+	TradeInfo
+	AmpBps uint32
+	Token0 common.Address
+	Token1 common.Address
+	KLast  *big.Int
+}
+
+// Reserves is the output of a Uniswap V2 pair's `getReserves`
+type Reserves struct {
+	Reserve0           *big.Int
+	Reserve1           *big.Int
+	BlockTimestampLast uint32
+}
+
+// UniswapV2Pair is the state of a Uniswap V2 pair
+type UniswapV2Pair struct {
+	Address common.Address
+	Reserves
+	Token0 common.Address
+	Token1 common.Address
+}
+
+// Snapshot is the state of many pools read at the same block.
+// Success tells, for each pool, whether all of its calls succeeded.
+type Snapshot[T any] struct {
+	BlockNumber *big.Int
+	BlockHash   common.Hash
+	Pools       []T
+	Success     []bool
+}
+
+// Fetcher fetches the state of many pools in bulk using `tryBlockAndAggregate`,
+// so that a failing pool does not fail the others.
+type Fetcher struct {
+	client        *ethrpc.Client
+	block         ethrpc.Block
+	tickWordRange int
+}
+
+// NewFetcher creates a new Fetcher using the client's multicall contract.
+func NewFetcher(client *ethrpc.Client) *Fetcher {
+	return &Fetcher{
+		client: client,
+	}
+}
+
+// SetBlock sets the block the state is fetched at, the latest block by default.
+// The pools are read at the hash of the block, see ethrpc.Client.PinBlock.
+func (f *Fetcher) SetBlock(block ethrpc.Block) *Fetcher {
+	f.block = block
+
+	return f
+}
+
+// GetDmmPools fetches the trade info, amplification, tokens and kLast of DMM pools.
+func (f *Fetcher) GetDmmPools(ctx context.Context, pools []common.Address) (*Snapshot[DmmPool], error) {
+	req, header, err := f.newRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]DmmPool, len(pools))
+
+	for i, pool := range pools {
+		s := &states[i]
+		s.Address = pool
+		req.
+			AddCall(newCall(DmmPoolABI, pool, MethodGetTradeInfo), []interface{}{&s.TradeInfo}).
+			AddCall(newCall(DmmPoolABI, pool, MethodAmpBps), []interface{}{&s.AmpBps}).
+			AddCall(newCall(DmmPoolABI, pool, MethodToken0), []interface{}{&s.Token0}).
+			AddCall(newCall(DmmPoolABI, pool, MethodToken1), []interface{}{&s.Token1}).
+			AddCall(newCall(DmmPoolABI, pool, MethodKLast), []interface{}{&s.KLast})
+	}
+
+	return execute(req, header, states, 5)
+}
+
+// GetUniswapV2Pairs fetches the reserves and tokens of Uniswap V2 pairs.
+func (f *Fetcher) GetUniswapV2Pairs(ctx context.Context, pairs []common.Address) (*Snapshot[UniswapV2Pair], error) {
+	req, header, err := f.newRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]UniswapV2Pair, len(pairs))
+
+	for i, pair := range pairs {
+		s := &states[i]
+		s.Address = pair
+		req.
+			AddCall(newCall(UniswapV2PairABI, pair, MethodGetReserves), []interface{}{&s.Reserves}).
+			AddCall(newCall(UniswapV2PairABI, pair, MethodToken0), []interface{}{&s.Token0}).
+			AddCall(newCall(UniswapV2PairABI, pair, MethodToken1), []interface{}{&s.Token1})
+	}
+
+	return execute(req, header, states, 3)
+}
+
+// newRequest creates a request pinned to the hash of the fetcher's block, whose header is returned along with it.
+func (f *Fetcher) newRequest(ctx context.Context) (*ethrpc.Request, *types.Header, error) {
+	block, header, err := f.client.PinBlock(ctx, f.block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f.client.NewRequest().SetContext(ctx).SetBlock(block), header, nil
+}
+
+// execute executes the request pinned to the header, which has `callsPerPool` calls for each pool in order,
+// into a snapshot.
+func execute[T any](req *ethrpc.Request, header *types.Header, states []T, callsPerPool int) (*Snapshot[T], error) {
+	res, err := req.TryBlockAndAggregateInChunks()
+	if err != nil {
+		logger.Errorf("failed to fetch pools, err: %v", err)
+		return nil, err
+	}
+
+	success := make([]bool, len(states))
+	for i := range states {
+		success[i] = true
+		for _, ok := range res.Result[i*callsPerPool : (i+1)*callsPerPool] {
+			success[i] = success[i] && ok
+		}
+	}
+
+	return &Snapshot[T]{
+		BlockNumber: header.Number,
+		BlockHash:   header.Hash(),
+		Pools:       states,
+		Success:     success,
+	}, nil
+}
+
+func newCall(poolABI abi.ABI, pool common.Address, method string, params ...interface{}) *ethrpc.Call {
+	return &ethrpc.Call{
+		ABI:    poolABI,
+		Target: pool.Hex(),
+		Method: method,
+		Params: params,
+	}
+}
@@ -0,0 +1,97 @@
+package pools
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/internal/ethtest"
+)
+
+func TestGetUniswapV2PairsSameBlock(t *testing.T) {
+	ec := ethtest.NewMulticall(nil)
+	head := ec.Head
+	ec.Call = func(block ethrpc.Block, pair common.Address, data []byte) ([]byte, error) {
+		// a new block is mined after each call, changing the reserves
+		ec.Head = &types.Header{Number: new(big.Int).Add(ec.Head.Number, common.Big1)}
+		require.Equal(t, head.Hash(), block.Hash)
+
+		method, err := UniswapV2PairABI.MethodById(data[:4])
+		if err != nil {
+			return nil, err
+		}
+		if method.Name == MethodGetReserves {
+			return method.Outputs.Pack(ec.Head.Number, ec.Head.Number, uint32(0))
+		}
+
+		return method.Outputs.Pack(pair)
+	}
+
+	client := ethrpc.NewWithClient(ec).SetMulticallChunkSize(2)
+	snapshot, err := NewFetcher(client).
+		GetUniswapV2Pairs(context.Background(), []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")})
+	require.NoError(t, err)
+	require.Equal(t, head.Number, snapshot.BlockNumber)
+	require.Equal(t, head.Hash(), snapshot.BlockHash)
+	require.Equal(t, []bool{true, true}, snapshot.Success)
+}
+
+func TestGetDmmPools(t *testing.T) {
+	pool := common.HexToAddress("0x01")
+	noKLast := common.HexToAddress("0x02")
+	token0, token1 := common.HexToAddress("0xa0"), common.HexToAddress("0xa1")
+
+	ec := ethtest.NewMulticall(func(_ ethrpc.Block, target common.Address, data []byte) ([]byte, error) {
+		method, err := DmmPoolABI.MethodById(data[:4])
+		if err != nil {
+			return nil, err
+		}
+
+		switch method.Name {
+		case MethodGetTradeInfo:
+			return method.Outputs.Pack(big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4), big.NewInt(5))
+		case MethodAmpBps:
+			return method.Outputs.Pack(uint32(20000))
+		case MethodToken0:
+			return method.Outputs.Pack(token0)
+		case MethodToken1:
+			return method.Outputs.Pack(token1)
+		case MethodKLast:
+			if target == noKLast {
+				return nil, errors.New("execution reverted")
+			}
+			return method.Outputs.Pack(big.NewInt(6))
+		}
+
+		return nil, errors.New("unexpected method " + method.Name)
+	})
+
+	snapshot, err := NewFetcher(ethrpc.NewWithClient(ec)).
+		GetDmmPools(context.Background(), []common.Address{pool, noKLast})
+	require.NoError(t, err)
+	require.Equal(t, ec.Head.Number, snapshot.BlockNumber)
+	require.Equal(t, ec.Head.Hash(), snapshot.BlockHash)
+	require.Equal(t, []bool{true, false}, snapshot.Success)
+
+	require.Equal(t, DmmPool{
+		Address: pool,
+		TradeInfo: TradeInfo{
+			Reserve0:       big.NewInt(1),
+			Reserve1:       big.NewInt(2),
+			VReserve0:      big.NewInt(3),
+			VReserve1:      big.NewInt(4),
+			FeeInPrecision: big.NewInt(5),
+		},
+		AmpBps: 20000,
+		Token0: token0,
+		Token1: token1,
+		KLast:  big.NewInt(6),
+	}, snapshot.Pools[0])
+	require.Equal(t, token1, snapshot.Pools[1].Token1)
+}
@@ -0,0 +1,240 @@
+package pools
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/PandaRR007/ethrpc"
+)
+
+const (
+	MethodFee         = "fee"
+	MethodLiquidity   = "liquidity"
+	MethodSlot0       = "slot0"
+	MethodTickBitmap  = "tickBitmap"
+	MethodTickSpacing = "tickSpacing"
+	MethodTicks       = "ticks"
+
+	// MinTick and MaxTick are the bounds of ticks of Uniswap V3 pools
+	MinTick = -887272
+	MaxTick = 887272
+)
+
+// Slot0 is the output of a Uniswap V3 pool's `slot0`
+type Slot0 struct {
+	SqrtPriceX96               *big.Int
+	Tick                       *big.Int
+	ObservationIndex           uint16
+	ObservationCardinality     uint16
+	ObservationCardinalityNext uint16
+	FeeProtocol                uint8
+	Unlocked                   bool
+}
+
+// TickInfo is the output of a Uniswap V3 pool's `ticks`
+type TickInfo struct {
+	LiquidityGross                 *big.Int
+	LiquidityNet                   *big.Int
+	FeeGrowthOutside0X128          *big.Int
+	FeeGrowthOutside1X128          *big.Int
+	TickCumulativeOutside          *big.Int
+	SecondsPerLiquidityOutsideX128 *big.Int
+	SecondsOutside                 uint32
+	Initialized                    bool
+}
+
+// Tick is an initialized tick of a Uniswap V3 pool
+type Tick struct {
+	Index int32
+	TickInfo
+}
+
+// UniswapV3Pool is the state of a Uniswap V3 pool
+type UniswapV3Pool struct {
+	Address     common.Address
+	Slot0       Slot0
+	Liquidity   *big.Int
+	Fee         *big.Int
+	TickSpacing *big.Int
+	Token0      common.Address
+	Token1      common.Address
+	// TickBitmap contains the fetched words of the tick bitmap, keyed by word position
+	TickBitmap map[int16]*big.Int
+	// Ticks contains the initialized ticks of the fetched bitmap words, sorted by index
+	Ticks []Tick
+}
+
+// SetTickWordRange limits the tick bitmap words fetched for Uniswap V3 pools to `wordRange` words
+// on each side of the word of the current tick. Zero, the default, fetches the whole bitmap.
+func (f *Fetcher) SetTickWordRange(wordRange int) *Fetcher {
+	f.tickWordRange = wordRange
+
+	return f
+}
+
+// GetUniswapV3Pools fetches the state of Uniswap V3 pools along with their initialized ticks.
+// Pools are read in three phases: the pool state, the words of the tick bitmap, and then only the
+// initialized ticks. All of them are pinned to the hash of the same block, see ethrpc.Client.PinBlock.
+// A pool is not successful if any of its calls failed, including the tick bitmap and ticks ones.
+func (f *Fetcher) GetUniswapV3Pools(ctx context.Context, pools []common.Address) (*Snapshot[UniswapV3Pool], error) {
+	req, header, err := f.newRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	block := req.Block()
+
+	states := make([]UniswapV3Pool, len(pools))
+
+	for i, pool := range pools {
+		s := &states[i]
+		s.Address = pool
+		req.
+			AddCall(newCall(UniswapV3PoolABI, pool, MethodSlot0), []interface{}{&s.Slot0}).
+			AddCall(newCall(UniswapV3PoolABI, pool, MethodLiquidity), []interface{}{&s.Liquidity}).
+			AddCall(newCall(UniswapV3PoolABI, pool, MethodFee), []interface{}{&s.Fee}).
+			AddCall(newCall(UniswapV3PoolABI, pool, MethodTickSpacing), []interface{}{&s.TickSpacing}).
+			AddCall(newCall(UniswapV3PoolABI, pool, MethodToken0), []interface{}{&s.Token0}).
+			AddCall(newCall(UniswapV3PoolABI, pool, MethodToken1), []interface{}{&s.Token1})
+	}
+
+	snapshot, err := execute(req, header, states, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = f.getTickBitmaps(ctx, block, snapshot); err != nil {
+		return nil, err
+	}
+
+	if err = f.getTicks(ctx, block, snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// getTickBitmaps fetches the tick bitmap words of the pools whose state was fetched.
+func (f *Fetcher) getTickBitmaps(ctx context.Context, block ethrpc.Block, snapshot *Snapshot[UniswapV3Pool]) error {
+	type word struct {
+		pool     int
+		position int16
+		value    *big.Int
+	}
+
+	var words []*word
+	req := f.client.NewRequest().SetContext(ctx).SetBlock(block)
+
+	for i := range snapshot.Pools {
+		s := &snapshot.Pools[i]
+		if !snapshot.Success[i] || s.TickSpacing == nil || s.TickSpacing.Sign() <= 0 || s.Slot0.Tick == nil {
+			continue
+		}
+
+		s.TickBitmap = make(map[int16]*big.Int)
+		spacing := int(s.TickSpacing.Int64())
+		minWord, maxWord := wordPosition(MinTick, spacing), wordPosition(MaxTick, spacing)
+
+		if f.tickWordRange > 0 {
+			current := wordPosition(int(s.Slot0.Tick.Int64()), spacing)
+			if current-f.tickWordRange > minWord {
+				minWord = current - f.tickWordRange
+			}
+			if current+f.tickWordRange < maxWord {
+				maxWord = current + f.tickWordRange
+			}
+		}
+
+		for position := minWord; position <= maxWord; position++ {
+			w := &word{pool: i, position: int16(position)}
+			words = append(words, w)
+			req.AddCall(newCall(UniswapV3PoolABI, s.Address, MethodTickBitmap, w.position), []interface{}{&w.value})
+		}
+	}
+
+	if len(words) == 0 {
+		return nil
+	}
+
+	res, err := req.TryAggregateInChunks()
+	if err != nil {
+		logger.Errorf("failed to fetch tick bitmaps, err: %v", err)
+		return err
+	}
+
+	for i, w := range words {
+		if !res.Result[i] {
+			snapshot.Success[w.pool] = false
+			continue
+		}
+
+		if w.value != nil && w.value.Sign() != 0 {
+			snapshot.Pools[w.pool].TickBitmap[w.position] = w.value
+		}
+	}
+
+	return nil
+}
+
+// getTicks fetches the ticks which are initialized in the fetched tick bitmap words.
+func (f *Fetcher) getTicks(ctx context.Context, block ethrpc.Block, snapshot *Snapshot[UniswapV3Pool]) error {
+	req := f.client.NewRequest().SetContext(ctx).SetBlock(block)
+	// pools contains the index of the pool of each call
+	var pools []int
+
+	for i := range snapshot.Pools {
+		s := &snapshot.Pools[i]
+		if !snapshot.Success[i] || len(s.TickBitmap) == 0 {
+			continue
+		}
+
+		spacing := int(s.TickSpacing.Int64())
+		for position, value := range s.TickBitmap {
+			for bit := 0; bit < 256; bit++ {
+				if value.Bit(bit) == 1 {
+					index := (int(position)*256 + bit) * spacing
+					s.Ticks = append(s.Ticks, Tick{Index: int32(index)})
+				}
+			}
+		}
+
+		sort.Slice(s.Ticks, func(a, b int) bool { return s.Ticks[a].Index < s.Ticks[b].Index })
+
+		for j := range s.Ticks {
+			t := &s.Ticks[j]
+			req.AddCall(newCall(UniswapV3PoolABI, s.Address, MethodTicks, big.NewInt(int64(t.Index))), []interface{}{&t.TickInfo})
+			pools = append(pools, i)
+		}
+	}
+
+	if len(req.Calls) == 0 {
+		return nil
+	}
+
+	res, err := req.TryAggregateInChunks()
+	if err != nil {
+		logger.Errorf("failed to fetch ticks, err: %v", err)
+		return err
+	}
+
+	for i, ok := range res.Result {
+		if !ok {
+			snapshot.Success[pools[i]] = false
+		}
+	}
+
+	return nil
+}
+
+// wordPosition returns the position of the tick bitmap word containing the tick.
+func wordPosition(tick int, tickSpacing int) int {
+	compressed := tick / tickSpacing
+	if tick < 0 && tick%tickSpacing != 0 {
+		compressed--
+	}
+
+	return compressed >> 8
+}
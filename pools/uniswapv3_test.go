@@ -0,0 +1,87 @@
+package pools
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/PandaRR007/ethrpc"
+	"github.com/PandaRR007/ethrpc/internal/ethtest"
+)
+
+func TestWordPosition(t *testing.T) {
+	require.Equal(t, 0, wordPosition(0, 1))
+	require.Equal(t, 0, wordPosition(255, 1))
+	require.Equal(t, 1, wordPosition(256, 1))
+	require.Equal(t, -1, wordPosition(-1, 1))
+	require.Equal(t, -1, wordPosition(-60, 60))
+	require.Equal(t, -1, wordPosition(-59, 60))
+	require.Equal(t, -58, wordPosition(MinTick, 60))
+	require.Equal(t, 57, wordPosition(MaxTick, 60))
+}
+
+func TestGetUniswapV3PoolsFailingTicks(t *testing.T) {
+	healthy := common.HexToAddress("0x01")
+	failingBitmap := common.HexToAddress("0x02")
+	failingTicks := common.HexToAddress("0x03")
+
+	client := ethrpc.NewWithClient(ethtest.NewMulticall(func(_ ethrpc.Block, pool common.Address, data []byte) ([]byte, error) {
+		method, err := UniswapV3PoolABI.MethodById(data[:4])
+		if err != nil {
+			return nil, err
+		}
+
+		switch method.Name {
+		case MethodSlot0:
+			return method.Outputs.Pack(big.NewInt(1), big.NewInt(0), uint16(0), uint16(1), uint16(1), uint8(0), true)
+		case MethodLiquidity:
+			return method.Outputs.Pack(big.NewInt(1000))
+		case MethodFee:
+			return method.Outputs.Pack(big.NewInt(3000))
+		case MethodTickSpacing:
+			return method.Outputs.Pack(big.NewInt(60))
+		case MethodToken0, MethodToken1:
+			return method.Outputs.Pack(common.Address{})
+		case MethodTickBitmap:
+			args, _ := method.Inputs.Unpack(data[4:])
+			if pool == failingBitmap && args[0].(int16) == 1 {
+				return nil, errors.New("execution reverted")
+			}
+			if args[0].(int16) == 0 {
+				// tick 60 is initialized
+				return method.Outputs.Pack(big.NewInt(2))
+			}
+			return method.Outputs.Pack(big.NewInt(0))
+		case MethodTicks:
+			if pool == failingTicks {
+				return nil, errors.New("execution reverted")
+			}
+			one := big.NewInt(1)
+			return method.Outputs.Pack(one, one, one, one, one, one, uint32(1), true)
+		default:
+			return nil, errors.New("execution reverted")
+		}
+	}))
+
+	snapshot, err := NewFetcher(client).SetTickWordRange(1).
+		GetUniswapV3Pools(context.Background(), []common.Address{healthy, failingBitmap, failingTicks})
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, false, false}, snapshot.Success)
+
+	pool := snapshot.Pools[0]
+	require.Len(t, pool.Ticks, 1)
+	require.Equal(t, int32(60), pool.Ticks[0].Index)
+	require.True(t, pool.Ticks[0].Initialized)
+}
+
+func TestGetUniswapV3PoolsPendingBlock(t *testing.T) {
+	client := ethrpc.NewWithClient(ethtest.NewMulticall(nil))
+
+	_, err := NewFetcher(client).SetBlock(ethrpc.AtBlockTag(ethrpc.BlockTagPending)).
+		GetUniswapV3Pools(context.Background(), []common.Address{{}})
+	require.ErrorIs(t, err, ethrpc.ErrPendingBlockNotPinnable)
+}
@@ -0,0 +1,196 @@
+package ethrpc
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// AccountProof is the result of `eth_getProof`
+type AccountProof struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageProof  `json:"storageProof"`
+}
+
+// StorageProof is the proof of a single storage slot of an account
+type StorageProof struct {
+	Key   string          `json:"key"`
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+// Slot returns the storage slot which is proven.
+func (p *StorageProof) Slot() common.Hash {
+	return common.HexToHash(p.Key)
+}
+
+// Verify checks the account proof and all of its storage proofs against the given state root.
+// It returns an error wrapping ErrInvalidProof if any of them does not match.
+func (p *AccountProof) Verify(stateRoot common.Hash) error {
+	value, err := trie.VerifyProof(stateRoot, crypto.Keccak256(p.Address.Bytes()), proofDB(p.AccountProof))
+	if err != nil {
+		return fmt.Errorf("%w: account %s: %v", ErrInvalidProof, p.Address, err)
+	}
+
+	balance := new(big.Int)
+	if p.Balance != nil {
+		balance = p.Balance.ToInt()
+	}
+
+	if value == nil {
+		// proof of absence, the account must be empty
+		if balance.Sign() != 0 || p.Nonce != 0 ||
+			(p.CodeHash != zeroHash && p.CodeHash != types.EmptyCodeHash) ||
+			(p.StorageHash != zeroHash && p.StorageHash != types.EmptyRootHash) {
+			return fmt.Errorf("%w: account %s does not exist", ErrInvalidProof, p.Address)
+		}
+	} else {
+		var account types.StateAccount
+		if err = rlp.DecodeBytes(value, &account); err != nil {
+			return fmt.Errorf("%w: account %s: %v", ErrInvalidProof, p.Address, err)
+		}
+
+		if account.Nonce != uint64(p.Nonce) || account.Balance.Cmp(balance) != 0 ||
+			account.Root != p.StorageHash || !bytes.Equal(account.CodeHash, p.CodeHash.Bytes()) {
+			return fmt.Errorf("%w: account %s does not match", ErrInvalidProof, p.Address)
+		}
+	}
+
+	for _, sp := range p.StorageProof {
+		if err = sp.Verify(p.StorageHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Verify checks the storage proof against the given storage root of its account.
+func (p *StorageProof) Verify(storageRoot common.Hash) error {
+	value, err := trie.VerifyProof(storageRoot, crypto.Keccak256(p.Slot().Bytes()), proofDB(p.Proof))
+	if err != nil {
+		return fmt.Errorf("%w: slot %s: %v", ErrInvalidProof, p.Key, err)
+	}
+
+	expected := new(big.Int)
+	if p.Value != nil {
+		expected = p.Value.ToInt()
+	}
+
+	actual := new(big.Int)
+	if value != nil {
+		var content []byte
+		if err = rlp.DecodeBytes(value, &content); err != nil {
+			return fmt.Errorf("%w: slot %s: %v", ErrInvalidProof, p.Key, err)
+		}
+		actual.SetBytes(content)
+	}
+
+	if actual.Cmp(expected) != 0 {
+		return fmt.Errorf("%w: slot %s does not match", ErrInvalidProof, p.Key)
+	}
+
+	return nil
+}
+
+// proofDB builds a key-value store of trie nodes, keyed by their hash, to verify a proof.
+func proofDB(proof []hexutil.Bytes) *memorydb.Database {
+	db := memorydb.New()
+	for _, node := range proof {
+		_ = db.Put(crypto.Keccak256(node), node)
+	}
+
+	return db
+}
+
+// getProof calls `eth_getProof` for the account and slots at the request's block.
+func (c *Client) getProof(req *Request, account common.Address, slots []common.Hash) (*AccountProof, error) {
+	if c.rpcClient == nil {
+		return nil, ErrRPCClientNotSet
+	}
+
+	// Avoid keys being 'null'.
+	if slots == nil {
+		slots = []common.Hash{}
+	}
+
+	block, err := req.Block().arg()
+	if err != nil {
+		return nil, err
+	}
+
+	var proof AccountProof
+	err = c.rpcClient.CallContext(req.Context(), &proof, "eth_getProof", account, slots, block)
+	if err != nil {
+		logger.Errorf("failed to call GetProof to %v, err: %v", account, err)
+		return nil, err
+	}
+
+	return &proof, nil
+}
+
+// VerifyRequested checks that the proof is for the requested account and slots, in order,
+// then verifies it against the given state root.
+func (p *AccountProof) VerifyRequested(stateRoot common.Hash, account common.Address, slots []common.Hash) error {
+	if p.Address != account {
+		return fmt.Errorf("%w: got account %s, requested %s", ErrInvalidProof, p.Address, account)
+	}
+
+	if len(p.StorageProof) != len(slots) {
+		return fmt.Errorf("%w: got %d slots, requested %d", ErrInvalidProof, len(p.StorageProof), len(slots))
+	}
+
+	for i, slot := range slots {
+		if p.StorageProof[i].Slot() != slot {
+			return fmt.Errorf("%w: got slot %s, requested %s", ErrInvalidProof, p.StorageProof[i].Key, slot)
+		}
+	}
+
+	return p.Verify(stateRoot)
+}
+
+// getVerifiedProof calls `eth_getProof` at the request's block hash and verifies the result
+// against the state root of the block header, which is checked to hash to the requested block hash.
+func (c *Client) getVerifiedProof(req *Request, account common.Address, slots []common.Hash) (*AccountProof, error) {
+	if req.BlockHash == zeroHash {
+		return nil, ErrBlockHashNotSet
+	}
+
+	header, err := c.ethClient.HeaderByHash(req.Context(), req.BlockHash)
+	if err != nil {
+		logger.Errorf("failed to get header %v, err: %v", req.BlockHash, err)
+		return nil, err
+	}
+
+	if header.Hash() != req.BlockHash {
+		err = fmt.Errorf("%w: header hashes to %s, requested %s", ErrInvalidProof, header.Hash(), req.BlockHash)
+		logger.Errorf("failed to verify header, err: %v", err)
+		return nil, err
+	}
+
+	proof, err := c.getProof(req, account, slots)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = proof.VerifyRequested(header.Root, account, slots); err != nil {
+		logger.Errorf("failed to verify proof of %v at %v, err: %v", account, req.BlockHash, err)
+		return nil, err
+	}
+
+	return proof, nil
+}
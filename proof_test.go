@@ -0,0 +1,132 @@
+package ethrpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+)
+
+type proofList []hexutil.Bytes
+
+func (l *proofList) Put(_ []byte, value []byte) error {
+	*l = append(*l, common.CopyBytes(value))
+	return nil
+}
+
+func (l *proofList) Delete([]byte) error {
+	return nil
+}
+
+// newTestProof builds the proof of an account with one storage slot, and the state root it's proven against
+func newTestProof(t *testing.T) (AccountProof, common.Hash) {
+	db := trie.NewDatabase(rawdb.NewMemoryDatabase())
+
+	slot := common.BigToHash(big.NewInt(8))
+	value := big.NewInt(123456789)
+	storageTrie := trie.NewEmpty(db)
+	encoded, _ := rlp.EncodeToBytes(value.Bytes())
+	storageTrie.Update(crypto.Keccak256(slot.Bytes()), encoded)
+
+	var storageProof proofList
+	require.NoError(t, storageTrie.Prove(crypto.Keccak256(slot.Bytes()), 0, &storageProof))
+
+	account := common.HexToAddress("0x5ba1e12693dc8f9c48aad8770482f4739beed696")
+	stateAccount := types.StateAccount{
+		Nonce:    1,
+		Balance:  big.NewInt(1e18),
+		Root:     storageTrie.Hash(),
+		CodeHash: types.EmptyCodeHash.Bytes(),
+	}
+	stateTrie := trie.NewEmpty(db)
+	encoded, _ = rlp.EncodeToBytes(&stateAccount)
+	stateTrie.Update(crypto.Keccak256(account.Bytes()), encoded)
+
+	var accountProof proofList
+	require.NoError(t, stateTrie.Prove(crypto.Keccak256(account.Bytes()), 0, &accountProof))
+
+	proof := AccountProof{
+		Address:      account,
+		AccountProof: accountProof,
+		Balance:      (*hexutil.Big)(stateAccount.Balance),
+		CodeHash:     types.EmptyCodeHash,
+		Nonce:        1,
+		StorageHash:  stateAccount.Root,
+		StorageProof: []StorageProof{{Key: slot.Hex(), Value: (*hexutil.Big)(value), Proof: storageProof}},
+	}
+
+	return proof, stateTrie.Hash()
+}
+
+func TestAccountProofVerify(t *testing.T) {
+	proof, root := newTestProof(t)
+	require.NoError(t, proof.Verify(root))
+
+	proof.StorageProof[0].Value = (*hexutil.Big)(big.NewInt(1))
+	require.ErrorIs(t, proof.Verify(root), ErrInvalidProof)
+
+	proof.Balance = (*hexutil.Big)(big.NewInt(1))
+	require.ErrorIs(t, proof.Verify(root), ErrInvalidProof)
+}
+
+// proofClient serves a header by hash and an `eth_getProof` result
+type proofClient struct {
+	EthClient
+
+	header *types.Header
+	proof  AccountProof
+}
+
+func (c *proofClient) HeaderByHash(context.Context, common.Hash) (*types.Header, error) {
+	return c.header, nil
+}
+
+func (c *proofClient) CallContext(_ context.Context, result interface{}, _ string, _ ...interface{}) error {
+	*result.(*AccountProof) = c.proof
+	return nil
+}
+
+func (c *proofClient) BatchCallContext(context.Context, []rpc.BatchElem) error {
+	return nil
+}
+
+func TestGetVerifiedProof(t *testing.T) {
+	proof, root := newTestProof(t)
+	header := &types.Header{Number: big.NewInt(1), Root: root}
+	slot := proof.StorageProof[0].Slot()
+
+	getProof := func(pc *proofClient, account common.Address, slots []common.Hash) error {
+		client := NewWithClient(pc).SetRPCClient(pc)
+		_, err := client.R().SetBlockHash(header.Hash()).GetVerifiedProof(account, slots)
+		return err
+	}
+
+	pc := &proofClient{header: header, proof: proof}
+	require.NoError(t, getProof(pc, proof.Address, []common.Hash{slot}))
+
+	// a made-up header with another state root
+	forged := &types.Header{Number: big.NewInt(1), Root: common.HexToHash("0x01")}
+	err := getProof(&proofClient{header: forged, proof: proof}, proof.Address, []common.Hash{slot})
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	// the proof of another account
+	err = getProof(pc, common.HexToAddress("0x01"), []common.Hash{slot})
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	// a missing slot
+	err = getProof(pc, proof.Address, []common.Hash{slot, common.HexToHash("0x09")})
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	// a substituted slot
+	err = getProof(pc, proof.Address, []common.Hash{common.HexToHash("0x09")})
+	require.ErrorIs(t, err, ErrInvalidProof)
+}
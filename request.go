@@ -40,6 +40,19 @@ func (c *Call) autofillUnpackABI() {
 	}
 }
 
+// unpack unpacks the returned data of the call into its output,
+// trying each of the UnpackABI in order until one succeeds.
+func (c *Call) unpack(data []byte) error {
+	var err error
+	for j, unpackABI := range c.UnpackABI {
+		if err = unpackABI.UnpackIntoInterface(c.Output[j], c.Method, data); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
 type Request struct {
 	client         *Client
 	Method         string
@@ -47,8 +60,15 @@ type Request struct {
 	Calls          []*Call
 	ctx            context.Context
 	RawCallMsg     ethereum.CallMsg
+	RawCallMsgs    []ethereum.CallMsg
 	BlockNumber    *big.Int
 	BlockHash      common.Hash
+	// RequireCanonical makes requests at BlockHash fail if the block is not in the canonical chain
+	RequireCanonical bool
+	// BlockTag selects a named block such as `finalized`, it takes precedence over BlockNumber
+	BlockTag BlockTag
+	// WithBlockTimestamp appends a `getCurrentBlockTimestamp` call to multicall requests
+	WithBlockTimestamp bool
 }
 
 // Context method returns the Context if it's already set in request
@@ -85,6 +105,23 @@ func (r *Request) SetRequireSuccess(requireSuccess bool) *Request {
 	return r
 }
 
+// SetWithBlockTimestamp makes multicall requests also return the block timestamp in `Response.BlockTimestamp`,
+// consistent with the other calls since it is read in the same multicall.
+func (r *Request) SetWithBlockTimestamp(withBlockTimestamp bool) *Request {
+	r.WithBlockTimestamp = withBlockTimestamp
+
+	return r
+}
+
+// multiCallSize returns the number of calls sent in the multicall, including the block timestamp call.
+func (r *Request) multiCallSize() int {
+	if r.WithBlockTimestamp {
+		return len(r.Calls) + 1
+	}
+
+	return len(r.Calls)
+}
+
 func (r *Request) SetBlockNumber(blockNumber *big.Int) *Request {
 	r.BlockNumber = blockNumber
 
@@ -97,12 +134,84 @@ func (r *Request) SetBlockHash(blockHash common.Hash) *Request {
 	return r
 }
 
+// SetBlockTag sets the named block, such as `safe` or `finalized`, the request is executed at.
+// Requests at the `pending` block are sent with `PendingCallContract`.
+func (r *Request) SetBlockTag(blockTag BlockTag) *Request {
+	r.BlockTag = blockTag
+
+	return r
+}
+
+// SetBlock sets the block the request is executed at, replacing any block number, hash or tag set before.
+func (r *Request) SetBlock(block Block) *Request {
+	r.BlockNumber = block.Number
+	r.BlockHash = block.Hash
+	r.RequireCanonical = block.RequireCanonical
+	r.BlockTag = block.Tag
+
+	return r
+}
+
+// Block returns the block the request is executed at.
+func (r *Request) Block() Block {
+	return Block{
+		Number:           r.BlockNumber,
+		Hash:             r.BlockHash,
+		RequireCanonical: r.RequireCanonical,
+		Tag:              r.BlockTag,
+	}
+}
+
 func (r *Request) Execute(method string) (*Response, error) {
 	r.Method = method
 
 	return r.client.execute(r)
 }
 
+// ExecuteInChunks executes the request with the given multicall method, splitting its calls into multicalls
+// of at most the client's multicall chunk size. The results of all chunks are merged into one response.
+// When there are several chunks, they are all pinned to the hash of the request's block, the latest one
+// if no block is set, so that they read the same state, see Client.PinBlock.
+func (r *Request) ExecuteInChunks(method string) (*Response, error) {
+	chunkSize := r.client.chunkSize(len(r.Calls))
+	if len(r.Calls) <= chunkSize {
+		return r.Execute(method)
+	}
+
+	r.Method = method
+	response := &Response{
+		Request: r,
+	}
+
+	block, _, err := r.client.PinBlock(r.Context(), r.Block())
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(r.Calls); start += chunkSize {
+		end := start + chunkSize
+		if end > len(r.Calls) {
+			end = len(r.Calls)
+		}
+
+		chunk := *r
+		chunk.Calls = r.Calls[start:end]
+		chunk.SetBlock(block)
+
+		res, err := chunk.Execute(method)
+		if err != nil {
+			return nil, err
+		}
+
+		response.Result = append(response.Result, res.Result...)
+		response.BlockNumber = res.BlockNumber
+		response.BlockHash = res.BlockHash
+		response.BlockTimestamp = res.BlockTimestamp
+	}
+
+	return response, nil
+}
+
 func (r *Request) Call() (*Response, error) {
 	return r.Execute(MethodCall)
 }
@@ -115,6 +224,17 @@ func (r *Request) TryAggregate() (*Response, error) {
 	return r.Execute(MethodTryAggregate)
 }
 
+// TryAggregateInChunks is like TryAggregate but splits the calls into several multicalls, see ExecuteInChunks.
+func (r *Request) TryAggregateInChunks() (*Response, error) {
+	return r.ExecuteInChunks(MethodTryAggregate)
+}
+
+// TryBlockAndAggregateInChunks is like TryBlockAndAggregate but splits the calls into several multicalls
+// pinned to the same block, see ExecuteInChunks.
+func (r *Request) TryBlockAndAggregateInChunks() (*Response, error) {
+	return r.ExecuteInChunks(MethodTryBlockAndAggregate)
+}
+
 func (r *Request) GetCurrentBlockTimestamp() (uint64, error) {
 	res, err := r.Execute(MethodGetCurrentBlockTimestamp)
 	if err != nil {
@@ -133,10 +253,38 @@ func (r *Request) GetCurrentBlockTimestamp() (uint64, error) {
 	return blockTimestamp, nil
 }
 
+// GetStorageAt reads a storage slot at the request's block number, hash or tag, and unpacks it with the `abi.Arguments`.
 func (r *Request) GetStorageAt(account common.Address, key common.Hash, abi abi.Arguments) ([]interface{}, error) {
-	return r.client.getStorageAt(r.Context(), account, key, abi)
+	return r.client.getStorageAt(r, account, key, abi)
+}
+
+// BatchGetStorageAt reads many storage slots at the request's block number or hash in one round trip,
+// and unpacks each slot with its own `abi.Arguments`. Results are in the same order as the slots.
+func (r *Request) BatchGetStorageAt(slots []StorageSlot) ([][]interface{}, error) {
+	return r.client.batchGetStorageAt(r, slots)
+}
+
+// GetProof returns the account and storage proofs of `eth_getProof` at the request's block number or hash.
+// The proofs are not verified, see GetVerifiedProof.
+func (r *Request) GetProof(account common.Address, slots []common.Hash) (*AccountProof, error) {
+	return r.client.getProof(r, account, slots)
+}
+
+// GetVerifiedProof returns the account and storage proofs at the request's block hash,
+// after verifying them against the state root of the block header fetched by its hash.
+// The block hash must be set with SetBlockHash.
+func (r *Request) GetVerifiedProof(account common.Address, slots []common.Hash) (*AccountProof, error) {
+	return r.client.getVerifiedProof(r, account, slots)
 }
 
 func (r *Request) TryBlockAndAggregate() (*Response, error) {
 	return r.Execute(MethodTryBlockAndAggregate)
 }
+
+// Batch sends every call as its own `eth_call` inside JSON-RPC batches,
+// which is useful for targets that can not be called through the multicall contract.
+// The batch is split into chunks of the client's batch size, and the error of each call is
+// available in `Response.Errors`.
+func (r *Request) Batch() (*Response, error) {
+	return r.Execute(MethodBatch)
+}
@@ -1,11 +1,25 @@
 package ethrpc
 
-import "math/big"
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
 
 type Response struct {
 	Request     *Request
 	BlockNumber *big.Int
-	RawResponse []byte
-	// Result is an array that contains response result for all calls in the request
+	// BlockHash is only set for tryBlockAndAggregate requests pinned to a block hash and responses of a Watcher,
+	// as the multicall can't read the hash of the block it's executed in
+	BlockHash common.Hash
+	// BlockTimestamp is only set for multicall requests with WithBlockTimestamp and responses of a Watcher
+	BlockTimestamp uint64
+	RawResponse    []byte
+	// RawResponses contains the raw response of each call, only set for batch requests
+	RawResponses [][]byte
+	// Errors contains the error of each call, only set for batch requests
+	Errors []error
+	// Result is an array that contains response result for all calls in the request,
+	// a call whose returned data cannot be unpacked into its output is considered failed
 	Result []bool
 }
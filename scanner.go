@@ -0,0 +1,150 @@
+package ethrpc
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+const (
+	// DefaultScanStepSize is the default number of blocks a LogScanner processes at once
+	DefaultScanStepSize = 10000
+
+	// DefaultScanPollInterval is the default interval a LogScanner polls for new blocks once it reached the head
+	DefaultScanPollInterval = 5 * time.Second
+)
+
+// LogHandler handles the decoded logs of the block range [from, to].
+// The range is checkpointed once the handler returns without error.
+type LogHandler func(ctx context.Context, from, to uint64, events []DecodedEvent) error
+
+// LogScanner walks the logs matching a query from a start block to the head, then follows new blocks.
+// Logs are fetched with FetchLogs, decoded with the ABI and delivered to the handler range by range.
+// The last processed block is persisted in a checkpoint store so that the scanner resumes after restarts.
+type LogScanner struct {
+	client       *Client
+	query        ethereum.FilterQuery
+	abi          abi.ABI
+	handler      LogHandler
+	store        CheckpointStore
+	startBlock   uint64
+	stepSize     uint64
+	pollInterval time.Duration
+}
+
+// NewLogScanner creates a new LogScanner of the logs matching the query, whose block range is ignored.
+// The last processed block is persisted in the store, usually a FileCheckpointStore, which is required.
+func (c *Client) NewLogScanner(
+	query ethereum.FilterQuery, contractABI abi.ABI, store CheckpointStore, handler LogHandler,
+) *LogScanner {
+	return &LogScanner{
+		client:       c,
+		query:        query,
+		abi:          contractABI,
+		handler:      handler,
+		store:        store,
+		stepSize:     DefaultScanStepSize,
+		pollInterval: DefaultScanPollInterval,
+	}
+}
+
+// SetStartBlock sets the block scanned first when there is no checkpoint yet.
+func (s *LogScanner) SetStartBlock(startBlock uint64) *LogScanner {
+	s.startBlock = startBlock
+
+	return s
+}
+
+// SetStepSize sets the number of blocks processed at once.
+func (s *LogScanner) SetStepSize(stepSize uint64) *LogScanner {
+	s.stepSize = stepSize
+
+	return s
+}
+
+// SetPollInterval sets the interval to poll for new blocks once the scanner reached the head.
+func (s *LogScanner) SetPollInterval(pollInterval time.Duration) *LogScanner {
+	s.pollInterval = pollInterval
+
+	return s
+}
+
+// Run scans until the context is done or the handler fails.
+// It returns ErrCheckpointStoreNotSet if the scanner has no checkpoint store.
+func (s *LogScanner) Run(ctx context.Context) error {
+	if s.store == nil {
+		return ErrCheckpointStoreNotSet
+	}
+
+	from := s.startBlock
+	last, ok, err := s.store.Load()
+	if err != nil {
+		logger.Errorf("failed to load checkpoint, err: %v", err)
+		return err
+	}
+	if ok {
+		from = last + 1
+	}
+
+	for {
+		head, err := s.client.GetBlockNumber(ctx)
+		if err != nil {
+			logger.Errorf("failed to get block number, err: %v", err)
+			return err
+		}
+
+		if from > head {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(s.pollInterval):
+				continue
+			}
+		}
+
+		to := head
+		if s.stepSize > 0 && to-from+1 > s.stepSize {
+			to = from + s.stepSize - 1
+		}
+
+		if err = s.scan(ctx, from, to); err != nil {
+			return err
+		}
+
+		from = to + 1
+	}
+}
+
+// scan processes the block range [from, to] and checkpoints it.
+func (s *LogScanner) scan(ctx context.Context, from, to uint64) error {
+	query := s.query
+	query.BlockHash = nil
+	query.FromBlock = new(big.Int).SetUint64(from)
+	query.ToBlock = new(big.Int).SetUint64(to)
+
+	logs, err := s.client.FetchLogs(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	events, err := DecodeEvents(s.abi, logs)
+	if err != nil {
+		logger.Errorf("failed to decode logs [%d, %d], err: %v", from, to, err)
+		return err
+	}
+
+	if err = s.handler(ctx, from, to, events); err != nil {
+		return err
+	}
+
+	if err = s.store.Save(to); err != nil {
+		logger.Errorf("failed to save checkpoint %d, err: %v", to, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package ethrpc
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogScannerResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	ec := &logsEthClient{head: 99, maxResults: 100}
+	client := NewWithClient(ec)
+
+	// scan runs a scanner on the checkpoint file until it processed the stop block
+	scan := func(stop uint64) []uint64 {
+		var scanned []uint64
+		ctx, cancel := context.WithCancel(context.Background())
+		handler := func(_ context.Context, from, to uint64, events []DecodedEvent) error {
+			scanned = append(scanned, from, to)
+
+			require.Len(t, events, int(to-from+1))
+			for i, event := range events {
+				require.Equal(t, "Transfer", event.Event.Name)
+				require.Equal(t, new(big.Int).SetUint64(from+uint64(i)), event.Fields["value"])
+			}
+
+			if to == stop {
+				cancel()
+			}
+			return nil
+		}
+
+		err := client.NewLogScanner(ethereum.FilterQuery{}, dmmPoolABI, NewFileCheckpointStore(path), handler).
+			SetStartBlock(10).
+			SetStepSize(50).
+			Run(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+
+		return scanned
+	}
+
+	require.Equal(t, []uint64{10, 59, 60, 99}, scan(99))
+
+	block, ok, err := NewFileCheckpointStore(path).Load()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, uint64(99), block)
+
+	// a new scanner on the same file resumes after the checkpoint instead of the start block
+	ec.head = 129
+	require.Equal(t, []uint64{100, 129}, scan(129))
+}
+
+func TestLogScannerWithoutStore(t *testing.T) {
+	client := NewWithClient(&logsEthClient{head: 99, maxResults: 100})
+	handler := func(context.Context, uint64, uint64, []DecodedEvent) error {
+		return nil
+	}
+
+	err := client.NewLogScanner(ethereum.FilterQuery{}, dmmPoolABI, nil, handler).Run(context.Background())
+	require.ErrorIs(t, err, ErrCheckpointStoreNotSet)
+}
@@ -0,0 +1,98 @@
+package ethrpc
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// slotSize is the size of a storage slot in bytes
+const slotSize = 32
+
+// tt256 is 2^256, storage slots wrap around it
+var tt256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// MappingSlot returns the slot of `mapping[key]` for a mapping stored at `slot`,
+// where key is a value type already padded to 32 bytes, see AddressKey and Uint256Key.
+func MappingSlot(key common.Hash, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(key.Bytes(), slot.Bytes())
+}
+
+// MappingSlotBytes returns the slot of `mapping[key]` for a mapping with `string` or `bytes` keys stored at `slot`.
+func MappingSlotBytes(key []byte, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(key, slot.Bytes())
+}
+
+// AddressKey pads an address to be used as a mapping key.
+func AddressKey(addr common.Address) common.Hash {
+	return common.BytesToHash(addr.Bytes())
+}
+
+// Uint256Key pads an unsigned integer to be used as a mapping key.
+func Uint256Key(n *big.Int) common.Hash {
+	return common.BigToHash(n)
+}
+
+// SlotAdd returns `slot + n`.
+func SlotAdd(slot common.Hash, n *big.Int) common.Hash {
+	sum := new(big.Int).Add(slot.Big(), n)
+
+	return common.BigToHash(sum.Mod(sum, tt256))
+}
+
+// DynamicArrayDataSlot returns the slot where the elements of a dynamic array stored at `slot` start.
+// The array length itself is stored at `slot`.
+func DynamicArrayDataSlot(slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(slot.Bytes())
+}
+
+// ArrayElementSlot returns the slot and the byte offset inside that slot of element `index`
+// of an array whose elements start at `dataSlot` and take `elemSize` bytes each.
+// Elements of at most 16 bytes are packed into the same slot, bigger ones start at a new slot.
+func ArrayElementSlot(dataSlot common.Hash, index *big.Int, elemSize uint64) (common.Hash, uint64) {
+	if elemSize == 0 || elemSize > slotSize/2 {
+		slotsPerElem := (elemSize + slotSize - 1) / slotSize
+		if slotsPerElem == 0 {
+			slotsPerElem = 1
+		}
+
+		return SlotAdd(dataSlot, new(big.Int).Mul(index, new(big.Int).SetUint64(slotsPerElem))), 0
+	}
+
+	elemsPerSlot := new(big.Int).SetUint64(slotSize / elemSize)
+	slotIndex, elemIndex := new(big.Int).DivMod(index, elemsPerSlot, new(big.Int))
+
+	return SlotAdd(dataSlot, slotIndex), elemIndex.Uint64() * elemSize
+}
+
+// DynamicArrayElementSlot returns the slot and the byte offset of element `index`
+// of a dynamic array stored at `slot`, whose elements take `elemSize` bytes each.
+func DynamicArrayElementSlot(slot common.Hash, index *big.Int, elemSize uint64) (common.Hash, uint64) {
+	return ArrayElementSlot(DynamicArrayDataSlot(slot), index, elemSize)
+}
+
+// UnpackPacked extracts a packed field of `size` bytes at byte `offset` from a 32-byte storage word.
+// As in solc storage layouts, the offset is counted from the lower-order (right) end of the word.
+func UnpackPacked(word common.Hash, offset, size uint64) *big.Int {
+	if offset >= slotSize {
+		return new(big.Int)
+	}
+	if offset+size > slotSize {
+		size = slotSize - offset
+	}
+
+	end := slotSize - offset
+
+	return new(big.Int).SetBytes(word[end-size : end])
+}
+
+// UnpackPackedSigned is like UnpackPacked but interprets the field as a two's complement signed integer.
+func UnpackPackedSigned(word common.Hash, offset, size uint64) *big.Int {
+	v := UnpackPacked(word, offset, size)
+	if size == 0 || size > slotSize || v.Bit(int(size*8-1)) == 0 {
+		return v
+	}
+
+	return v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(size*8)))
+}
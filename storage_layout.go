@@ -0,0 +1,258 @@
+package ethrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// StorageLayout is the `storageLayout` output of solc
+type StorageLayout struct {
+	Storage []StorageLayoutEntry         `json:"storage"`
+	Types   map[string]StorageLayoutType `json:"types"`
+}
+
+// StorageLayoutEntry is a state variable, or a struct member, in a storage layout
+type StorageLayoutEntry struct {
+	Label  string `json:"label"`
+	Offset uint64 `json:"offset"`
+	Slot   string `json:"slot"`
+	Type   string `json:"type"`
+}
+
+// StorageLayoutType describes how a type is encoded in storage
+type StorageLayoutType struct {
+	Encoding      string               `json:"encoding"`
+	Label         string               `json:"label"`
+	NumberOfBytes string               `json:"numberOfBytes"`
+	Key           string               `json:"key,omitempty"`
+	Value         string               `json:"value,omitempty"`
+	Base          string               `json:"base,omitempty"`
+	Members       []StorageLayoutEntry `json:"members,omitempty"`
+}
+
+// StorageLocation is the resolved location of a variable in storage
+type StorageLocation struct {
+	Slot common.Hash
+	// Offset is the byte offset of the variable inside the slot, counted from the lower-order end
+	Offset uint64
+	// Size is the number of bytes taken by the variable
+	Size uint64
+	// Type is the type label of the variable, e.g. `uint112`
+	Type string
+}
+
+// Unpack extracts the variable from the 32-byte storage word of its slot.
+func (l *StorageLocation) Unpack(word common.Hash) *big.Int {
+	return UnpackPacked(word, l.Offset, l.Size)
+}
+
+// ParseStorageLayout parses a solc storage layout, either the `storageLayout` object itself
+// or a contract output which contains it.
+func ParseStorageLayout(data []byte) (*StorageLayout, error) {
+	var output struct {
+		StorageLayout *StorageLayout `json:"storageLayout"`
+	}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+	if output.StorageLayout != nil {
+		return output.StorageLayout, nil
+	}
+
+	var layout StorageLayout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return nil, err
+	}
+
+	return &layout, nil
+}
+
+// Locate resolves the storage location of a variable path such as `totalSupply`,
+// `balances[0x5ba1e12693dc8f9c48aad8770482f4739beed696]`, `allowances[0x..][0x..]`,
+// `observations[3].blockTimestamp` or `names["foo"]`.
+func (l *StorageLayout) Locate(path string) (*StorageLocation, error) {
+	name, rest := splitStoragePath(path)
+
+	var entry *StorageLayoutEntry
+	for i := range l.Storage {
+		if l.Storage[i].Label == name {
+			entry = &l.Storage[i]
+			break
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w: %s", ErrStorageVariableNotFound, name)
+	}
+
+	slot, ok := new(big.Int).SetString(entry.Slot, 10)
+	if !ok {
+		return nil, fmt.Errorf("%w: slot %s", ErrInvalidStoragePath, entry.Slot)
+	}
+	loc := StorageLocation{Slot: common.BigToHash(slot), Offset: entry.Offset}
+	typeID := entry.Type
+
+	for rest != "" {
+		t, ok := l.Types[typeID]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown type %s", ErrInvalidStoragePath, typeID)
+		}
+
+		switch rest[0] {
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidStoragePath, path)
+			}
+			key := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			switch {
+			case t.Encoding == "mapping":
+				keyBytes, err := encodeMappingKey(l.Types[t.Key].Label, key)
+				if err != nil {
+					return nil, err
+				}
+				loc.Slot, loc.Offset = crypto.Keccak256Hash(keyBytes, loc.Slot.Bytes()), 0
+				typeID = t.Value
+			case t.Encoding == "dynamic_array" || (t.Encoding == "inplace" && t.Base != ""):
+				index, ok := new(big.Int).SetString(key, 0)
+				if !ok || index.Sign() < 0 {
+					return nil, fmt.Errorf("%w: index %s", ErrInvalidStoragePath, key)
+				}
+				elemSize, err := l.typeSize(t.Base)
+				if err != nil {
+					return nil, err
+				}
+
+				dataSlot := loc.Slot
+				if t.Encoding == "dynamic_array" {
+					dataSlot = DynamicArrayDataSlot(loc.Slot)
+				}
+				loc.Slot, loc.Offset = ArrayElementSlot(dataSlot, index, elemSize)
+				typeID = t.Base
+			default:
+				return nil, fmt.Errorf("%w: %s is not indexable", ErrInvalidStoragePath, t.Label)
+			}
+		case '.':
+			var member string
+			member, rest = splitStoragePath(rest[1:])
+
+			var m *StorageLayoutEntry
+			for i := range t.Members {
+				if t.Members[i].Label == member {
+					m = &t.Members[i]
+					break
+				}
+			}
+			if m == nil {
+				return nil, fmt.Errorf("%w: %s has no member %s", ErrInvalidStoragePath, t.Label, member)
+			}
+
+			memberSlot, ok := new(big.Int).SetString(m.Slot, 10)
+			if !ok {
+				return nil, fmt.Errorf("%w: slot %s", ErrInvalidStoragePath, m.Slot)
+			}
+			loc.Slot, loc.Offset = SlotAdd(loc.Slot, memberSlot), m.Offset
+			typeID = m.Type
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidStoragePath, path)
+		}
+	}
+
+	size, err := l.typeSize(typeID)
+	if err != nil {
+		return nil, err
+	}
+	loc.Size = size
+	loc.Type = l.Types[typeID].Label
+
+	return &loc, nil
+}
+
+// Slot resolves the storage slot of a variable path, see Locate.
+func (l *StorageLayout) Slot(path string) (common.Hash, error) {
+	loc, err := l.Locate(path)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return loc.Slot, nil
+}
+
+func (l *StorageLayout) typeSize(typeID string) (uint64, error) {
+	t, ok := l.Types[typeID]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown type %s", ErrInvalidStoragePath, typeID)
+	}
+
+	size, err := strconv.ParseUint(t.NumberOfBytes, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: size of %s", ErrInvalidStoragePath, t.Label)
+	}
+
+	return size, nil
+}
+
+// splitStoragePath splits the leading identifier of a path from the rest of it.
+func splitStoragePath(path string) (string, string) {
+	end := strings.IndexAny(path, "[.")
+	if end < 0 {
+		return strings.TrimSpace(path), ""
+	}
+
+	return strings.TrimSpace(path[:end]), path[end:]
+}
+
+// encodeMappingKey encodes a mapping key literal of the given type as it is hashed by solidity.
+func encodeMappingKey(typeLabel string, key string) ([]byte, error) {
+	switch {
+	case typeLabel == "string":
+		return []byte(strings.Trim(key, `"'`)), nil
+	case typeLabel == "bytes":
+		return hexutil.Decode(key)
+	case typeLabel == "bool":
+		b, err := strconv.ParseBool(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bool key %s", ErrInvalidStoragePath, key)
+		}
+		if b {
+			return common.BigToHash(big.NewInt(1)).Bytes(), nil
+		}
+		return common.Hash{}.Bytes(), nil
+	case typeLabel == "address" || strings.HasPrefix(typeLabel, "address ") || strings.HasPrefix(typeLabel, "contract "):
+		if !common.IsHexAddress(key) {
+			return nil, fmt.Errorf("%w: address key %s", ErrInvalidStoragePath, key)
+		}
+		return AddressKey(common.HexToAddress(key)).Bytes(), nil
+	case strings.HasPrefix(typeLabel, "bytes"):
+		b, err := hexutil.Decode(key)
+		if err != nil || len(b) > slotSize {
+			return nil, fmt.Errorf("%w: %s key %s", ErrInvalidStoragePath, typeLabel, key)
+		}
+		return common.RightPadBytes(b, slotSize), nil
+	case strings.HasPrefix(typeLabel, "uint") || strings.HasPrefix(typeLabel, "enum "):
+		n, ok := new(big.Int).SetString(key, 0)
+		if !ok || n.Sign() < 0 || n.Cmp(tt256) >= 0 {
+			return nil, fmt.Errorf("%w: %s key %s", ErrInvalidStoragePath, typeLabel, key)
+		}
+		return Uint256Key(n).Bytes(), nil
+	case strings.HasPrefix(typeLabel, "int"):
+		n, ok := new(big.Int).SetString(key, 0)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s key %s", ErrInvalidStoragePath, typeLabel, key)
+		}
+		if n.Sign() < 0 {
+			n.Add(n, tt256)
+		}
+		return Uint256Key(n).Bytes(), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %s", ErrInvalidStoragePath, typeLabel)
+	}
+}
@@ -0,0 +1,80 @@
+package ethrpc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const testStorageLayout = `{
+	"storage": [
+		{"label": "balances", "offset": 0, "slot": "0", "type": "t_mapping(t_address,t_uint256)"},
+		{"label": "reserve0", "offset": 0, "slot": "1", "type": "t_uint112"},
+		{"label": "reserve1", "offset": 14, "slot": "1", "type": "t_uint112"},
+		{"label": "blockTimestampLast", "offset": 28, "slot": "1", "type": "t_uint32"},
+		{"label": "observations", "offset": 0, "slot": "2", "type": "t_array(t_struct(Observation)_storage)dyn_storage"}
+	],
+	"types": {
+		"t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+		"t_uint32": {"encoding": "inplace", "label": "uint32", "numberOfBytes": "4"},
+		"t_uint112": {"encoding": "inplace", "label": "uint112", "numberOfBytes": "14"},
+		"t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+		"t_mapping(t_address,t_uint256)": {"encoding": "mapping", "key": "t_address", "label": "mapping(address => uint256)", "numberOfBytes": "32", "value": "t_uint256"},
+		"t_array(t_struct(Observation)_storage)dyn_storage": {"encoding": "dynamic_array", "label": "struct Observation[]", "numberOfBytes": "32", "base": "t_struct(Observation)_storage"},
+		"t_struct(Observation)_storage": {"encoding": "inplace", "label": "struct Observation", "numberOfBytes": "64", "members": [
+			{"label": "timestamp", "offset": 0, "slot": "0", "type": "t_uint32"},
+			{"label": "price", "offset": 0, "slot": "1", "type": "t_uint256"}
+		]}
+	}
+}`
+
+func TestStorageSlots(t *testing.T) {
+	require.Equal(t,
+		common.HexToHash("0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563"),
+		DynamicArrayDataSlot(common.Hash{}),
+	)
+
+	slot, offset := ArrayElementSlot(common.Hash{}, big.NewInt(9), 4)
+	require.Equal(t, common.BigToHash(big.NewInt(1)), slot)
+	require.Equal(t, uint64(4), offset)
+
+	slot, offset = ArrayElementSlot(common.Hash{}, big.NewInt(3), 64)
+	require.Equal(t, common.BigToHash(big.NewInt(6)), slot)
+	require.Equal(t, uint64(0), offset)
+}
+
+func TestUnpackPacked(t *testing.T) {
+	word := common.HexToHash("0x12345678000000000000000000000000000200000000000000000000000000ff")
+
+	require.Equal(t, big.NewInt(0xff), UnpackPacked(word, 0, 14))
+	require.Equal(t, big.NewInt(2), UnpackPacked(word, 14, 14))
+	require.Equal(t, big.NewInt(0x12345678), UnpackPacked(word, 28, 4))
+	require.Equal(t, big.NewInt(-1), UnpackPackedSigned(word, 0, 1))
+}
+
+func TestStorageLayoutLocate(t *testing.T) {
+	layout, err := ParseStorageLayout([]byte(testStorageLayout))
+	require.NoError(t, err)
+
+	holder := common.HexToAddress("0x5ba1e12693dc8f9c48aad8770482f4739beed696")
+	loc, err := layout.Locate("balances[" + holder.Hex() + "]")
+	require.NoError(t, err)
+	require.Equal(t, MappingSlot(AddressKey(holder), common.Hash{}), loc.Slot)
+	require.Equal(t, uint64(32), loc.Size)
+
+	loc, err = layout.Locate("blockTimestampLast")
+	require.NoError(t, err)
+	require.Equal(t, common.BigToHash(big.NewInt(1)), loc.Slot)
+	require.Equal(t, uint64(28), loc.Offset)
+	require.Equal(t, uint64(4), loc.Size)
+
+	loc, err = layout.Locate("observations[2].price")
+	require.NoError(t, err)
+	require.Equal(t, SlotAdd(DynamicArrayDataSlot(common.BigToHash(big.NewInt(2))), big.NewInt(5)), loc.Slot)
+	require.Equal(t, "uint256", loc.Type)
+
+	_, err = layout.Locate("unknown")
+	require.ErrorIs(t, err, ErrStorageVariableNotFound)
+}
@@ -0,0 +1,308 @@
+package ethrpc
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+const (
+	// DefaultResubscribeMaxBackoff is the default maximum wait between failed subscribe attempts
+	DefaultResubscribeMaxBackoff = 30 * time.Second
+
+	// recentSetSize is the number of recently delivered headers or logs remembered for deduplication
+	recentSetSize = 4096
+)
+
+// Subscriber wraps the head and log subscriptions of a websocket client, resubscribing with backoff when they fail.
+// Headers and logs missed while resubscribing are backfilled through the backfill client,
+// and duplicates are dropped, so that the consumer sees a gap-free stream.
+type Subscriber struct {
+	client     *Client
+	backfill   *Client
+	maxBackoff time.Duration
+}
+
+// NewSubscriber creates a new Subscriber backfilling through the same client.
+func (c *Client) NewSubscriber() *Subscriber {
+	return &Subscriber{
+		client:     c,
+		backfill:   c,
+		maxBackoff: DefaultResubscribeMaxBackoff,
+	}
+}
+
+// SetBackfillClient sets the client used to backfill missed headers and logs, usually an HTTP client.
+func (s *Subscriber) SetBackfillClient(client *Client) *Subscriber {
+	s.backfill = client
+
+	return s
+}
+
+// SetMaxBackoff sets the maximum wait between failed subscribe attempts.
+func (s *Subscriber) SetMaxBackoff(maxBackoff time.Duration) *Subscriber {
+	s.maxBackoff = maxBackoff
+
+	return s
+}
+
+// SubscribeNewHead subscribes to new heads until the context is done or the subscription is unsubscribed.
+// When a head is received more than one block after the previous one, the headers in between are backfilled.
+func (s *Subscriber) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) ethereum.Subscription {
+	var (
+		last *types.Header
+		seen = newRecentSet[common.Hash](recentSetSize)
+	)
+
+	return event.ResubscribeErr(s.maxBackoff, func(subCtx context.Context, lastErr error) (event.Subscription, error) {
+		if ctx.Err() != nil {
+			return doneSubscription(), nil
+		}
+		if lastErr != nil {
+			logger.Errorf("head subscription failed, resubscribing, err: %v", lastErr)
+		}
+
+		heads := make(chan *types.Header, 16)
+		sub, err := s.client.ethClient.SubscribeNewHead(subCtx, heads)
+		if err != nil {
+			logger.Errorf("failed to subscribe new heads, err: %v", err)
+			return nil, err
+		}
+
+		return event.NewSubscription(func(quit <-chan struct{}) error {
+			defer sub.Unsubscribe()
+
+			// deliver returns false when the subscription is stopped
+			deliver := func(head *types.Header) (bool, error) {
+				headers, err := s.backfillHeaders(ctx, last, head)
+				if err != nil {
+					return false, err
+				}
+
+				for _, header := range headers {
+					if seen.has(header.Hash()) {
+						continue
+					}
+
+					select {
+					case ch <- header:
+						seen.add(header.Hash())
+						last = header
+					case <-quit:
+						return false, nil
+					case <-ctx.Done():
+						return false, nil
+					}
+				}
+
+				return true, nil
+			}
+
+			for {
+				select {
+				case <-quit:
+					return nil
+				case <-ctx.Done():
+					return nil
+				case err := <-sub.Err():
+					// deliver the heads received before the failure
+					for len(heads) > 0 {
+						if ok, err := deliver(<-heads); !ok {
+							return err
+						}
+					}
+					return subscriptionErr(err)
+				case head := <-heads:
+					if ok, err := deliver(head); !ok {
+						return err
+					}
+				}
+			}
+		}), nil
+	})
+}
+
+// backfillHeaders returns the headers after last up to head.
+func (s *Subscriber) backfillHeaders(ctx context.Context, last, head *types.Header) ([]*types.Header, error) {
+	if last == nil || head.Number.Uint64() <= last.Number.Uint64()+1 {
+		return []*types.Header{head}, nil
+	}
+
+	var headers []*types.Header
+	for n := last.Number.Uint64() + 1; n < head.Number.Uint64(); n++ {
+		header, err := s.backfill.ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			logger.Errorf("failed to backfill header %d, err: %v", n, err)
+			return nil, err
+		}
+		headers = append(headers, header)
+	}
+
+	return append(headers, head), nil
+}
+
+// logKey identifies a delivered log
+type logKey struct {
+	blockHash common.Hash
+	index     uint
+	removed   bool
+}
+
+// SubscribeFilterLogs subscribes to the logs matching the query until the context is done
+// or the subscription is unsubscribed. The block range of the query is only used as the start of the stream:
+// logs from its FromBlock, or from the current block if it is nil, are backfilled on every (re)subscribe.
+func (s *Subscriber) SubscribeFilterLogs(
+	ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log,
+) ethereum.Subscription {
+	var (
+		next    uint64
+		started = query.FromBlock != nil
+		seen    = newRecentSet[logKey](recentSetSize)
+	)
+	if started {
+		next = query.FromBlock.Uint64()
+	}
+
+	liveQuery := query
+	liveQuery.FromBlock, liveQuery.ToBlock = nil, nil
+
+	return event.ResubscribeErr(s.maxBackoff, func(subCtx context.Context, lastErr error) (event.Subscription, error) {
+		if ctx.Err() != nil {
+			return doneSubscription(), nil
+		}
+		if lastErr != nil {
+			logger.Errorf("log subscription failed, resubscribing, err: %v", lastErr)
+		}
+
+		if !started {
+			latest, err := s.backfill.ethClient.BlockNumber(subCtx)
+			if err != nil {
+				logger.Errorf("failed to get block number, err: %v", err)
+				return nil, err
+			}
+			next, started = latest+1, true
+		}
+
+		logs := make(chan types.Log, 128)
+		sub, err := s.client.ethClient.SubscribeFilterLogs(subCtx, liveQuery, logs)
+		if err != nil {
+			logger.Errorf("failed to subscribe logs, err: %v", err)
+			return nil, err
+		}
+
+		return event.NewSubscription(func(quit <-chan struct{}) error {
+			defer sub.Unsubscribe()
+
+			deliver := func(l types.Log) bool {
+				key := logKey{blockHash: l.BlockHash, index: l.Index, removed: l.Removed}
+				if seen.has(key) {
+					return true
+				}
+
+				select {
+				case ch <- l:
+					seen.add(key)
+					if l.Removed {
+						// the block may be reorged back in
+						seen.remove(logKey{blockHash: l.BlockHash, index: l.Index})
+					}
+					// the block of the last log may still have undelivered logs, so it's backfilled again
+					if l.BlockNumber > next {
+						next = l.BlockNumber
+					}
+					return true
+				case <-quit:
+					return false
+				case <-ctx.Done():
+					return false
+				}
+			}
+
+			backfillQuery := query
+			backfillQuery.FromBlock, backfillQuery.ToBlock = new(big.Int).SetUint64(next), nil
+			backfilled, err := s.backfill.FetchLogs(ctx, backfillQuery)
+			if err != nil {
+				return err
+			}
+			for _, l := range backfilled {
+				if !deliver(l) {
+					return nil
+				}
+			}
+
+			for {
+				select {
+				case <-quit:
+					return nil
+				case <-ctx.Done():
+					return nil
+				case err := <-sub.Err():
+					// deliver the logs received before the failure
+					for len(logs) > 0 {
+						if !deliver(<-logs) {
+							return nil
+						}
+					}
+					return subscriptionErr(err)
+				case l := <-logs:
+					if !deliver(l) {
+						return nil
+					}
+				}
+			}
+		}), nil
+	})
+}
+
+// subscriptionErr makes sure a closed subscription is resubscribed.
+func subscriptionErr(err error) error {
+	if err == nil {
+		return ErrSubscriptionClosed
+	}
+
+	return err
+}
+
+// doneSubscription returns a subscription which ends without error, stopping the resubscribe loop.
+func doneSubscription() event.Subscription {
+	return event.NewSubscription(func(<-chan struct{}) error {
+		return nil
+	})
+}
+
+// recentSet is a set remembering its most recently added items
+type recentSet[T comparable] struct {
+	items map[T]struct{}
+	order []T
+	size  int
+}
+
+func newRecentSet[T comparable](size int) *recentSet[T] {
+	return &recentSet[T]{items: make(map[T]struct{}, size), size: size}
+}
+
+func (s *recentSet[T]) has(item T) bool {
+	_, ok := s.items[item]
+
+	return ok
+}
+
+// add adds the item, forgetting the oldest one when the set is full.
+func (s *recentSet[T]) add(item T) {
+	s.items[item] = struct{}{}
+	s.order = append(s.order, item)
+	if len(s.order) > s.size {
+		delete(s.items, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+func (s *recentSet[T]) remove(item T) {
+	delete(s.items, item)
+}
@@ -0,0 +1,160 @@
+package ethrpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/require"
+)
+
+// subEthClient serves a canonical chain with one log per block, and scripted subscriptions
+// delivering their items then failing, except the last one
+type subEthClient struct {
+	*chainEthClient
+
+	canonical []*types.Header
+	latest    atomic.Uint64
+	heads     [][]uint64
+	logs      []logScript
+	subs      int
+}
+
+// logScript is a log subscription, the latest block is set when it's established
+type logScript struct {
+	latest uint64
+	blocks []uint64
+}
+
+func newSubEthClient(n int) *subEthClient {
+	ec := &subEthClient{chainEthClient: &chainEthClient{headers: map[common.Hash]*types.Header{}}}
+	genesis := &types.Header{Number: big.NewInt(0)}
+	ec.headers[genesis.Hash()] = genesis
+	ec.canonical = append([]*types.Header{genesis}, ec.extend(genesis, n-1, 'a')...)
+	ec.latest.Store(uint64(n - 1))
+
+	return ec
+}
+
+func (c *subEthClient) BlockNumber(context.Context) (uint64, error) {
+	return c.latest.Load(), nil
+}
+
+func (c *subEthClient) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	return c.canonical[number.Uint64()], nil
+}
+
+func (c *subEthClient) log(number uint64) types.Log {
+	return types.Log{BlockHash: c.canonical[number].Hash(), BlockNumber: number}
+}
+
+func (c *subEthClient) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	for n := q.FromBlock.Uint64(); n <= q.ToBlock.Uint64(); n++ {
+		logs = append(logs, c.log(n))
+	}
+
+	return logs, nil
+}
+
+func (c *subEthClient) subscribe(items []uint64, last bool, send func(uint64, <-chan struct{})) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		for _, item := range items {
+			send(item, quit)
+		}
+		if last {
+			<-quit
+			return nil
+		}
+
+		return errors.New("connection lost")
+	})
+}
+
+func (c *subEthClient) SubscribeNewHead(_ context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	c.subs++
+	return c.subscribe(c.heads[c.subs-1], c.subs == len(c.heads), func(n uint64, quit <-chan struct{}) {
+		select {
+		case ch <- c.canonical[n]:
+		case <-quit:
+		}
+	}), nil
+}
+
+func (c *subEthClient) SubscribeFilterLogs(
+	_ context.Context, q ethereum.FilterQuery, ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	if q.FromBlock != nil || q.ToBlock != nil {
+		return nil, errors.New("invalid block range")
+	}
+
+	c.subs++
+	script := c.logs[c.subs-1]
+	c.latest.Store(script.latest)
+	return c.subscribe(script.blocks, c.subs == len(c.logs), func(n uint64, quit <-chan struct{}) {
+		select {
+		case ch <- c.log(n):
+		case <-quit:
+		}
+	}), nil
+}
+
+func receive[T any](t *testing.T, ch <-chan T, n int) []T {
+	var items []T
+	for len(items) < n {
+		select {
+		case item := <-ch:
+			items = append(items, item)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %d items, expected %d", len(items), n)
+		}
+	}
+
+	select {
+	case item := <-ch:
+		t.Fatalf("unexpected item %v", item)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	return items
+}
+
+func TestSubscribeNewHead(t *testing.T) {
+	ec := newSubEthClient(10)
+	ec.heads = [][]uint64{{1, 2}, {2, 5}, {5, 6}}
+
+	ch := make(chan *types.Header)
+	sub := NewWithClient(ec).NewSubscriber().SetMaxBackoff(time.Millisecond).
+		SubscribeNewHead(context.Background(), ch)
+	defer sub.Unsubscribe()
+
+	var numbers []uint64
+	for _, header := range receive(t, ch, 6) {
+		numbers = append(numbers, header.Number.Uint64())
+	}
+	require.Equal(t, []uint64{1, 2, 3, 4, 5, 6}, numbers)
+	require.Equal(t, 3, ec.subs)
+}
+
+func TestSubscribeFilterLogs(t *testing.T) {
+	ec := newSubEthClient(10)
+	ec.logs = []logScript{{3, []uint64{3, 4}}, {5, []uint64{5, 6, 7}}}
+
+	ch := make(chan types.Log)
+	sub := NewWithClient(ec).NewSubscriber().SetMaxBackoff(time.Millisecond).
+		SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{FromBlock: big.NewInt(2)}, ch)
+	defer sub.Unsubscribe()
+
+	var numbers []uint64
+	for _, l := range receive(t, ch, 6) {
+		numbers = append(numbers, l.BlockNumber)
+	}
+	require.Equal(t, []uint64{2, 3, 4, 5, 6, 7}, numbers)
+}
@@ -3,6 +3,7 @@ package ethrpc
 import (
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -28,3 +29,10 @@ type TryBlockAndAggregateResult struct {
 	BlockHash   [32]byte
 	ReturnData  []TryAggregateResultItem
 }
+
+// StorageSlot is a storage slot of an account to read, along with the arguments used to unpack it
+type StorageSlot struct {
+	Account common.Address
+	Key     common.Hash
+	ABI     abi.Arguments
+}
@@ -0,0 +1,223 @@
+package ethrpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/KyberNetwork/logger"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultWatchPollInterval is the default interval a Watcher polls for new heads
+// when the client does not support subscriptions
+const DefaultWatchPollInterval = 2 * time.Second
+
+// RequestBuilder adds the calls of the request a Watcher executes on each new head.
+// The request is already pinned to the head's hash. Outputs may be reused across requests.
+type RequestBuilder func(req *Request) error
+
+// ResponseHandler handles the response of a Watcher's request.
+type ResponseHandler func(ctx context.Context, res *Response) error
+
+// Watcher executes a multicall request on every new head, pinned to the head's hash.
+// Heads received while the handler is busy are skipped, except the latest one.
+type Watcher struct {
+	client       *Client
+	build        RequestBuilder
+	handler      ResponseHandler
+	method       string
+	onlyChanged  bool
+	pollInterval time.Duration
+}
+
+// NewWatcher creates a new Watcher executing its request with `tryAggregate`.
+func (c *Client) NewWatcher(build RequestBuilder, handler ResponseHandler) *Watcher {
+	return &Watcher{
+		client:       c,
+		build:        build,
+		handler:      handler,
+		method:       MethodTryAggregate,
+		pollInterval: DefaultWatchPollInterval,
+	}
+}
+
+// Watch executes the request built by build on every new head and passes its response to handler,
+// until the context is done or either of them fails. See Watcher.
+func (c *Client) Watch(ctx context.Context, build RequestBuilder, handler ResponseHandler) error {
+	return c.NewWatcher(build, handler).Run(ctx)
+}
+
+// SetMethod sets the multicall method the request is executed with, see Request.ExecuteInChunks.
+func (w *Watcher) SetMethod(method string) *Watcher {
+	w.method = method
+
+	return w
+}
+
+// SetOnlyChanged makes the watcher skip responses whose results and decoded outputs
+// are the same as the previous handled one.
+func (w *Watcher) SetOnlyChanged(onlyChanged bool) *Watcher {
+	w.onlyChanged = onlyChanged
+
+	return w
+}
+
+// SetPollInterval sets the interval to poll for new heads when the client does not support subscriptions.
+func (w *Watcher) SetPollInterval(pollInterval time.Duration) *Watcher {
+	w.pollInterval = pollInterval
+
+	return w
+}
+
+// Run watches new heads until the context is done, or the request builder or the handler fails.
+// Failing to execute the request at a head is logged and the head is skipped.
+func (w *Watcher) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// latest holds the latest head not handled yet, replacing the stale ones
+	latest := make(chan *types.Header, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- w.watchHeads(ctx, latest)
+	}()
+
+	var (
+		lastHead *types.Header
+		// last is a copy of the last handled response, its outputs can't be changed by the builder
+		last *watchedResponse
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errCh:
+			return err
+		case head := <-latest:
+			if lastHead != nil && lastHead.Hash() == head.Hash() {
+				continue
+			}
+			lastHead = head
+
+			req := w.client.R().SetContext(ctx).SetBlock(AtBlockHash(head.Hash(), false))
+			if err := w.build(req); err != nil {
+				return err
+			}
+
+			res, err := w.execute(req, head)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				continue
+			}
+
+			if w.onlyChanged {
+				if last != nil && !last.changed(res) {
+					continue
+				}
+				last = &watchedResponse{result: res.Result, outputs: cloneOutputs(res.Request.Calls)}
+			}
+
+			if err = w.handler(ctx, res); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// execute executes the request pinned to the head, filling the response's block info from the head.
+func (w *Watcher) execute(req *Request, head *types.Header) (*Response, error) {
+	res, err := req.ExecuteInChunks(w.method)
+	if err != nil {
+		logger.Errorf("failed to execute request at block %v, err: %v", head.Number, err)
+		return nil, err
+	}
+
+	res.BlockNumber = head.Number
+	res.BlockHash = head.Hash()
+	if res.BlockTimestamp == 0 {
+		res.BlockTimestamp = head.Time
+	}
+
+	return res, nil
+}
+
+// watchHeads sends the new heads to latest, replacing the head waiting there if any.
+// Heads are received with a resilient subscription if the client supports it, otherwise they are polled.
+func (w *Watcher) watchHeads(ctx context.Context, latest chan *types.Header) error {
+	offer := func(head *types.Header) {
+		select {
+		case <-latest:
+		default:
+		}
+		latest <- head
+	}
+
+	heads := make(chan *types.Header, 16)
+
+	probe, err := w.client.ethClient.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		logger.Debugf("failed to subscribe new heads, polling instead, err: %v", err)
+		return w.pollHeads(ctx, offer)
+	}
+	probe.Unsubscribe()
+
+	sub := w.client.NewSubscriber().SubscribeNewHead(ctx, heads)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		case head := <-heads:
+			offer(head)
+		}
+	}
+}
+
+func (w *Watcher) pollHeads(ctx context.Context, offer func(*types.Header)) error {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		head, err := w.client.ethClient.HeaderByNumber(ctx, nil)
+		if err != nil {
+			logger.Errorf("failed to get head, err: %v", err)
+		} else {
+			offer(head)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchedResponse is the results and a copy of the decoded outputs of a response
+type watchedResponse struct {
+	result  []bool
+	outputs [][]interface{}
+}
+
+// changed reports whether the results or decoded outputs of the response differ.
+func (w *watchedResponse) changed(res *Response) bool {
+	if len(w.result) != len(res.Result) || len(w.outputs) != len(res.Request.Calls) {
+		return true
+	}
+
+	for i, call := range res.Request.Calls {
+		if w.result[i] != res.Result[i] {
+			return true
+		}
+		if res.Result[i] && !outputsEqual(w.outputs[i], call.Output) {
+			return true
+		}
+	}
+
+	return false
+}
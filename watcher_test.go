@@ -0,0 +1,88 @@
+package ethrpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// watchEthClient does not support subscriptions, and moves its head forward each time it's called at the head
+type watchEthClient struct {
+	EthClient
+
+	supplies []int64
+	head     atomic.Int64
+}
+
+func (c *watchEthClient) header(number int64) *types.Header {
+	return &types.Header{Number: big.NewInt(number), Time: uint64(1000 + number)}
+}
+
+func (c *watchEthClient) SubscribeNewHead(context.Context, chan<- *types.Header) (ethereum.Subscription, error) {
+	return nil, errors.New("notifications not supported")
+}
+
+func (c *watchEthClient) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return c.header(c.head.Load()), nil
+}
+
+func (c *watchEthClient) CallContractAtHash(_ context.Context, _ ethereum.CallMsg, hash common.Hash) ([]byte, error) {
+	number := c.head.Load()
+	if hash != c.header(number).Hash() {
+		return nil, errors.New("unexpected block")
+	}
+	if number+1 < int64(len(c.supplies)) {
+		c.head.Add(1)
+	}
+
+	data, err := dmmPoolABI.Methods["totalSupply"].Outputs.Pack(big.NewInt(c.supplies[number]))
+	if err != nil {
+		return nil, err
+	}
+
+	return multicallABI.Methods[MethodTryAggregate].Outputs.Pack(TryAggregateResult{{Success: true, ReturnData: data}})
+}
+
+func TestWatchOnlyChanged(t *testing.T) {
+	for _, reuse := range []bool{false, true} {
+		ec := &watchEthClient{supplies: []int64{1, 1, 2, 2, 3}}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		// the builder may reuse the same output for every request
+		var reused *big.Int
+		build := func(req *Request) error {
+			supply := new(*big.Int)
+			if reuse {
+				supply = &reused
+			}
+			req.AddCall(&Call{ABI: dmmPoolABI, Target: common.Address{}.Hex(), Method: "totalSupply"}, []interface{}{supply})
+			return nil
+		}
+
+		var handled [][2]uint64
+		handler := func(_ context.Context, res *Response) error {
+			supply := *res.Request.Calls[0].Output[0].(**big.Int)
+			require.Equal(t, 1000+res.BlockNumber.Uint64(), res.BlockTimestamp)
+			handled = append(handled, [2]uint64{res.BlockNumber.Uint64(), supply.Uint64()})
+			if supply.Int64() == 3 {
+				cancel()
+			}
+			return nil
+		}
+
+		err := NewWithClient(ec).NewWatcher(build, handler).
+			SetOnlyChanged(true).
+			SetPollInterval(time.Millisecond).
+			Run(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, [][2]uint64{{0, 1}, {2, 2}, {4, 3}}, handled, "reuse: %v", reuse)
+	}
+}